@@ -2,194 +2,97 @@
 package day7
 
 import (
-	"container/list"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/circuit"
 )
 
-// instruction is an object representing each instruction line
-type instruction struct {
-	outWire string
-	gate    string
-	inWires []string
-	value   uint16
+// parseInput converts a wire name or signal in to a gate input
+func parseInput(s string) circuit.Input[uint16] {
+	if val, err := strconv.ParseUint(s, 10, 16); err == nil {
+		return circuit.Const(uint16(val))
+	}
+	return circuit.Wire[uint16](s)
 }
 
-// addInstruction adds an output wire to the given map if all inputs are already present
-func (i *instruction) addInstruction(m map[string]uint16) bool {
-	switch i.gate {
-
-	case "AND":
-		lhs, ok := m[i.inWires[0]]
-		if !ok {
-			return false
-		}
-		var rhs uint16
-		if len(i.inWires) > 1 {
-			rhs, ok = m[i.inWires[1]]
-			if !ok {
-				return false
-			}
-		} else {
-			rhs = i.value
-		}
-		m[i.outWire] = lhs & rhs
-
-	case "OR":
-		lhs, ok := m[i.inWires[0]]
-		if !ok {
-			return false
-		}
-		rhs, ok := m[i.inWires[1]]
-		if !ok {
-			return false
-		}
-		m[i.outWire] = lhs | rhs
-
-	case "NOT":
-		val, ok := m[i.inWires[0]]
-		if !ok {
-			return false
-		}
-		m[i.outWire] = ^val
+// parseInstruction converts the given string in to a gate
+func parseInstruction(line string) (circuit.Gate[uint16], error) {
+	lhs, out, found := strings.Cut(line, " -> ")
+	if !found {
+		return circuit.Gate[uint16]{}, fmt.Errorf("unable to parse instruction %q", line)
+	}
+	g := circuit.Gate[uint16]{Output: out}
 
-	case "LSHIFT":
-		val, ok := m[i.inWires[0]]
-		if !ok {
-			return false
-		}
-		m[i.outWire] = val << i.value
+	parts := strings.Fields(lhs)
+	switch len(parts) {
+	case 1:
+		g.Op = circuit.Buffer
+		g.Inputs = []circuit.Input[uint16]{parseInput(parts[0])}
 
-	case "RSHIFT":
-		val, ok := m[i.inWires[0]]
-		if !ok {
-			return false
+	case 2:
+		if parts[0] != "NOT" {
+			return circuit.Gate[uint16]{}, fmt.Errorf("unable to parse instruction %q", line)
 		}
-		m[i.outWire] = val >> i.value
-
-	default:
-		if len(i.inWires) > 0 {
-			val, ok := m[i.inWires[0]]
-			if !ok {
-				return false
+		g.Op = circuit.Not
+		g.Inputs = []circuit.Input[uint16]{parseInput(parts[1])}
+
+	case 3:
+		g.Op = circuit.Op(parts[1])
+		g.Inputs = []circuit.Input[uint16]{parseInput(parts[0])}
+		switch g.Op {
+		case circuit.And, circuit.Or:
+			g.Inputs = append(g.Inputs, parseInput(parts[2]))
+		case circuit.LShift, circuit.RShift:
+			shift, err := strconv.Atoi(parts[2])
+			if err != nil {
+				return circuit.Gate[uint16]{}, err
 			}
-			m[i.outWire] = val
-		} else {
-			m[i.outWire] = i.value
+			g.Shift = shift
+		default:
+			return circuit.Gate[uint16]{}, fmt.Errorf("unknown gate %q", parts[1])
 		}
 
+	default:
+		return circuit.Gate[uint16]{}, fmt.Errorf("unable to parse instruction %q", line)
 	}
 
-	return true
+	return g, nil
 }
 
-var (
-	andGatePattern    = regexp.MustCompile(`([a-z]+|[0-9]+) AND ([a-z]+)`)
-	orGatePattern     = regexp.MustCompile(`([a-z]+) OR ([a-z]+)`)
-	notGatePattern    = regexp.MustCompile(`NOT ([a-z]+)`)
-	lshiftGatePattern = regexp.MustCompile(`([a-z]+) LSHIFT ([0-9]+)`)
-	rshiftGatePattern = regexp.MustCompile(`([a-z]+) RSHIFT ([0-9]+)`)
-)
-
-// parseInstruction converts the given string in to an instruction object
-func parseInstruction(line string) instruction {
-	parts := strings.Split(line, " -> ")
-
-	i := instruction{outWire: parts[1]}
-	switch {
-	case strings.Contains(parts[0], "AND"):
-		matches := andGatePattern.FindStringSubmatch(parts[0])
-		i.gate = "AND"
-		if val, err := strconv.Atoi(matches[1]); err == nil {
-			i.value = uint16(val)
-		} else {
-			i.inWires = []string{matches[1]}
-		}
-		i.inWires = append(i.inWires, matches[2])
-
-	case strings.Contains(parts[0], "OR"):
-		matches := orGatePattern.FindStringSubmatch(parts[0])
-		i.gate = "OR"
-		i.inWires = []string{matches[1], matches[2]}
-
-	case strings.Contains(parts[0], "NOT"):
-		matches := notGatePattern.FindStringSubmatch(parts[0])
-		i.gate = "NOT"
-		i.inWires = []string{matches[1]}
-
-	case strings.Contains(parts[0], "LSHIFT"):
-		matches := lshiftGatePattern.FindStringSubmatch(parts[0])
-		i.gate = "LSHIFT"
-		i.inWires = []string{matches[1]}
-		if val, err := strconv.Atoi(matches[2]); err == nil {
-			i.value = uint16(val)
-		} else {
-			log.Fatal(err)
-		}
-
-	case strings.Contains(parts[0], "RSHIFT"):
-		matches := rshiftGatePattern.FindStringSubmatch(parts[0])
-		i.gate = "RSHIFT"
-		i.inWires = []string{matches[1]}
-		if val, err := strconv.Atoi(matches[2]); err == nil {
-			i.value = uint16(val)
-		} else {
-			log.Fatal(err)
-		}
-
-	default:
-		if val, err := strconv.Atoi(parts[0]); err == nil {
-			i.value = uint16(val)
-		} else {
-			i.inWires = []string{parts[0]}
+// parseCircuit converts the input lines in to a circuit
+func parseCircuit(lines []string) (*circuit.Circuit[uint16], error) {
+	gates := make([]circuit.Gate[uint16], len(lines))
+	for i, line := range lines {
+		g, err := parseInstruction(line)
+		if err != nil {
+			return nil, err
 		}
+		gates[i] = g
 	}
-
-	return i
+	return circuit.New(circuit.Uint16, gates)
 }
 
-func part1(instructions *list.List) uint16 {
-	wireSignals := make(map[string]uint16)
-
-	// Loop over all instructions until there is a signal on wire "a"
-	elem := instructions.Front()
-	for {
-
-		if _, ok := wireSignals["a"]; ok {
-			break
-		}
-
-		ins := elem.Value.(instruction)
-		elem2 := elem.Next()
-		if added := ins.addInstruction(wireSignals); added {
-			instructions.Remove(elem)
-		} else {
-			instructions.MoveToBack(elem)
-		}
-		elem = elem2
-
+// signalOnA returns the signal provided to wire "a"
+func signalOnA(c *circuit.Circuit[uint16]) (uint16, error) {
+	wires, err := c.Evaluate(nil)
+	if err != nil {
+		return 0, err
 	}
+	return wires["a"], nil
+}
 
-	fmt.Println(wireSignals["a"])
-	return wireSignals["a"]
+func part1(c *circuit.Circuit[uint16]) (uint16, error) {
+	return signalOnA(c)
 }
 
-func part2(instructions *list.List, wireB uint16) {
+func part2(c *circuit.Circuit[uint16], wireB uint16) (uint16, error) {
 	// Override value for wire "b"
-	for elem := instructions.Front(); elem != nil; elem = elem.Next() {
-		ins := elem.Value.(instruction)
-		if ins.outWire == "b" {
-			instructions.Remove(elem)
-			instructions.PushFront(instruction{"b", "", []string{}, wireB})
-			break
-		}
-	}
-
-	part1(instructions)
+	c.Override("b", wireB)
+	return signalOnA(c)
 }
 
 func Run(inputFile string) {
@@ -201,15 +104,20 @@ func Run(inputFile string) {
 	lines := strings.Split(
 		strings.TrimSuffix(string(data), "\n"), "\n",
 	)
+	c, err := parseCircuit(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	instructions := list.New()
-	instructions2 := list.New()
-	for _, line := range lines {
-		instruction := parseInstruction(line)
-		instructions.PushBack(instruction)
-		instructions2.PushBack(instruction)
+	p1, err := part1(c)
+	if err != nil {
+		log.Fatal(err)
 	}
+	fmt.Println("Part 1:", p1)
 
-	val := part1(instructions)
-	part2(instructions2, val)
+	p2, err := part2(c, p1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Part 2:", p2)
 }
@@ -7,6 +7,8 @@ import (
 	"log"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type Graph map[string]map[string]int
@@ -49,6 +51,19 @@ func makeAdjacencyList(data []byte) Graph {
 	return graph
 }
 
+// toDOT returns the routes between locations as a graph with the distances as edge labels
+func toDOT(graph Graph) *dot.Graph {
+	g := dot.New("routes", false)
+	for nodeA, subGraph := range graph {
+		for nodeB, distance := range subGraph {
+			if nodeA < nodeB {
+				g.Edge(nodeA, nodeB, dot.Attrs{"label": strconv.Itoa(distance)})
+			}
+		}
+	}
+	return g
+}
+
 // isExplored checks if a string is present in a list of strings
 func isExplored(explored []string, node string) bool {
 	for _, n := range explored {
@@ -114,6 +129,11 @@ func Run(inputFile string) {
 	}
 
 	graph := makeAdjacencyList(data)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(graph)); err != nil {
+			log.Fatal(err)
+		}
+	}
 	part1(graph)
 	part2(graph)
 }
@@ -0,0 +1,20 @@
+package day9
+
+import (
+	"testing"
+)
+
+var input = []byte(`London to Dublin = 464
+London to Belfast = 518
+Dublin to Belfast = 141`)
+
+func TestToDOT(t *testing.T) {
+	g := toDOT(makeAdjacencyList(input))
+	if got := len(g.Nodes()); got != 3 {
+		t.Errorf("toDOT() has %d nodes, want 3", got)
+	}
+	// Each route is held in both directions but drawn once
+	if got := g.Edges(); got != 3 {
+		t.Errorf("toDOT() has %d edges, want 3", got)
+	}
+}
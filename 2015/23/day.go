@@ -1,88 +1,80 @@
-// Advent of Code 2015 - Day 233
+// Advent of Code 2015 - Day 23
 package day23
 
 import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"strconv"
-	"strings"
-)
-
-// Instruction holds the information for a single instruction
-type Instruction struct {
-	name, register string
-	offset         int
-}
-
-// parseInstructions converts the input lines in to a slice of Instruction objects
-func parseInstructions(lines []string) []Instruction {
-	instructions := make([]Instruction, len(lines))
-
-	for i, line := range lines {
-		parts := strings.Split(line, " ")
-		instructions[i].name = parts[0]
-		instructions[i].register = strings.TrimSuffix(parts[1], ",")
-		if len(parts) == 3 {
-			if offset, err := strconv.Atoi(parts[2]); err == nil {
-				instructions[i].offset = offset
-			}
-			instructions[i].register = strings.TrimSuffix(parts[1], ",")
-		} else {
-			if parts[0] == "jmp" {
-				if offset, err := strconv.Atoi(parts[1]); err == nil {
-					instructions[i].offset = offset
-				}
-			} else {
-				instructions[i].register = parts[1]
-			}
-		}
-	}
-
-	return instructions
-}
-
-// solve follows a set of instuctions to operate on the given registers
-func solve(instructions []Instruction, registers map[string]uint) {
-	for i := 0; i < len(instructions); {
-
-		switch instructions[i].name {
-
-		case "hlf":
-			registers[instructions[i].register] /= 2
-			i++
-
-		case "tpl":
-			registers[instructions[i].register] *= 3
-			i++
 
-		case "inc":
-			registers[instructions[i].register] += 1
-			i++
-
-		case "jmp":
-			i += instructions[i].offset
+	"github.com/maze-mapper/advent-of-code/machine"
+)
 
-		case "jie":
-			if registers[instructions[i].register]%2 == 0 {
-				i += instructions[i].offset
-			} else {
-				i++
+// instructionSet holds the instructions of the computer, which has two registers "a" and "b"
+var instructionSet = machine.MustNewSet(
+	[]string{"a", "b"},
+	machine.Op{
+		Name:     "hlf",
+		Operands: []machine.OperandKind{machine.Register},
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Registers[args[0]] /= 2
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "tpl",
+		Operands: []machine.OperandKind{machine.Register},
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Registers[args[0]] *= 3
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "inc",
+		Operands: []machine.OperandKind{machine.Register},
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Registers[args[0]]++
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "jmp",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Jump(args[0])
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "jie",
+		Operands: []machine.OperandKind{machine.Register, machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			if m.Registers[args[0]]%2 == 0 {
+				m.Jump(args[1])
 			}
-
-		case "jio":
-			if registers[instructions[i].register] == 1 {
-				i += instructions[i].offset
-			} else {
-				i++
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "jio",
+		Operands: []machine.OperandKind{machine.Register, machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			if m.Registers[args[0]] == 1 {
+				m.Jump(args[1])
 			}
+			return nil
+		},
+	},
+)
 
-		default:
-			log.Fatal("Unrecognised instruction")
-
-		}
-
+// solve runs the program with the given initial value of register a and returns the final value of register b
+func solve(program []machine.Instruction, a int) (int, error) {
+	m := machine.New(instructionSet, program)
+	m.DetectLoops = true
+	m.SetRegister("a", a)
+	if err := m.Run(); err != nil {
+		return 0, err
 	}
+	return m.Register("b"), nil
 }
 
 func Run(inputFile string) {
@@ -90,20 +82,20 @@ func Run(inputFile string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	lines := strings.Split(
-		strings.TrimSuffix(string(data), "\n"), "\n",
-	)
-	instructions := parseInstructions(lines)
+	program, err := instructionSet.Parse(data)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	registers := map[string]uint{
-		"a": 0,
-		"b": 0,
+	p1, err := solve(program, 0)
+	if err != nil {
+		log.Fatal(err)
 	}
-	solve(instructions, registers)
-	fmt.Println("Part 1: Register b has value", registers["b"])
+	fmt.Println("Part 1: Register b has value", p1)
 
-	registers["a"] = 1
-	registers["b"] = 0
-	solve(instructions, registers)
-	fmt.Println("Part 2: Register b has value", registers["b"])
+	p2, err := solve(program, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Part 2: Register b has value", p2)
 }
@@ -7,6 +7,8 @@ import (
 	"log"
 	"sort"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/animate"
 )
 
 // Constants for cart direction, order is important
@@ -192,27 +194,34 @@ func tick(track [][]rune, carts map[coord]Cart) []coord {
 	return collisions
 }
 
-// printState prints the track with the current location of the carts
-func printState(track [][]rune, carts map[coord]Cart) {
+// renderState returns the track with the current location of the carts
+func renderState(track [][]rune, carts map[coord]Cart) [][]rune {
+	state := make([][]rune, len(track))
 	for i, row := range track {
+		state[i] = make([]rune, len(row))
 		for j, val := range row {
 			c := coord{j, i}
 			if cart, ok := carts[c]; ok {
-				switch cart.direction {
-				case Up:
-					fmt.Print("^")
-				case Right:
-					fmt.Print(">")
-				case Down:
-					fmt.Print("v")
-				case Left:
-					fmt.Print("<")
-				}
+				state[i][j] = []rune("^>v<")[cart.direction]
 			} else {
-				fmt.Print(string(val))
+				state[i][j] = val
 			}
 		}
-		fmt.Print("\n")
+	}
+	return state
+}
+
+// printState prints the track with the current location of the carts
+func printState(track [][]rune, carts map[coord]Cart) {
+	for _, row := range renderState(track, carts) {
+		fmt.Println(string(row))
+	}
+}
+
+// recordState records a frame of the track and carts if animation is enabled
+func recordState(track [][]rune, carts map[coord]Cart) {
+	if animate.Enabled() {
+		animate.Frame(renderState(track, carts))
 	}
 }
 
@@ -221,8 +230,10 @@ func Run(inputFile string) {
 	collisions := []coord{}
 
 	// Part 1
+	recordState(track, carts)
 	for len(collisions) == 0 {
 		collisions = tick(track, carts)
+		recordState(track, carts)
 	}
 	fmt.Println("Part 1: Collision at", collisions[0])
 
@@ -230,6 +241,7 @@ func Run(inputFile string) {
 	// Exit loop if there are zero or one carts
 	for len(carts) > 1 {
 		collisions = tick(track, carts)
+		recordState(track, carts)
 	}
 	for k := range carts {
 		fmt.Println("Part 2: Last cart at", k)
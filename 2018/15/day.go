@@ -9,6 +9,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/animate"
 )
 
 // coord holds grid coordinates
@@ -311,9 +313,11 @@ func determineOutcome(rounds int, units map[coord]*unit) int {
 func doCombat(area [][]rune, units map[coord]*unit) int {
 	round := 0
 	combatOver := false
+	recordState(area, units)
 	for !combatOver {
 		roundComplete := false
 		combatOver, roundComplete = doRound(area, units)
+		recordState(area, units)
 		if roundComplete {
 			round += 1
 		}
@@ -351,6 +355,27 @@ func countUnits(units map[coord]*unit, unitType int) int {
 	return count
 }
 
+// recordState records a frame of the area with the units in it if animation is enabled
+func recordState(area [][]rune, units map[coord]*unit) {
+	if !animate.Enabled() {
+		return
+	}
+	frame := make([][]rune, len(area))
+	for i, row := range area {
+		frame[i] = make([]rune, len(row))
+		copy(frame[i], row)
+	}
+	for c, u := range units {
+		switch u.unitType {
+		case Elf:
+			frame[c[1]][c[0]] = 'E'
+		case Goblin:
+			frame[c[1]][c[0]] = 'G'
+		}
+	}
+	animate.Frame(frame)
+}
+
 // printState prints the current game state for help with debugging
 func printState(area [][]rune, units map[coord]*unit) {
 	for i, row := range area {
@@ -7,16 +7,13 @@ import (
 	"log"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/2018/elfcode"
+	"github.com/maze-mapper/advent-of-code/machine"
 )
 
 type registers [4]int
 
-// Opcode holds unformation on a named opcode
-type Opcode struct {
-	name    string
-	a, b, c int
-}
-
 // uOpcode holds information on a numbered opcode
 type uOpcode struct {
 	number, a, b, c int
@@ -44,103 +41,23 @@ func stringToUOpcode(s string) uOpcode {
 	return op
 }
 
-// process will apply an opcode to the given registers
-func (o *Opcode) process(r *registers) {
-	switch o.name {
-
-	case "addr":
-		r[o.c] = r[o.a] + r[o.b]
-
-	case "addi":
-		r[o.c] = r[o.a] + o.b
-
-	case "mulr":
-		r[o.c] = r[o.a] * r[o.b]
-
-	case "muli":
-		r[o.c] = r[o.a] * o.b
-
-	case "banr":
-		r[o.c] = r[o.a] & r[o.b]
-
-	case "bani":
-		r[o.c] = r[o.a] & o.b
-
-	case "borr":
-		r[o.c] = r[o.a] | r[o.b]
-
-	case "bori":
-		r[o.c] = r[o.a] | o.b
-
-	case "setr":
-		r[o.c] = r[o.a]
-
-	case "seti":
-		r[o.c] = o.a
-
-	case "gtir":
-		if o.a > r[o.b] {
-			r[o.c] = 1
-		} else {
-			r[o.c] = 0
-		}
-
-	case "gtri":
-		if r[o.a] > o.b {
-			r[o.c] = 1
-		} else {
-			r[o.c] = 0
-		}
-
-	case "gtrr":
-		if r[o.a] > r[o.b] {
-			r[o.c] = 1
-		} else {
-			r[o.c] = 0
-		}
-
-	case "eqir":
-		if o.a == r[o.b] {
-			r[o.c] = 1
-		} else {
-			r[o.c] = 0
-		}
-
-	case "eqri":
-		if r[o.a] == o.b {
-			r[o.c] = 1
-		} else {
-			r[o.c] = 0
-		}
-
-	case "eqrr":
-		if r[o.a] == r[o.b] {
-			r[o.c] = 1
-		} else {
-			r[o.c] = 0
-		}
+// elfcodeSet is the instruction set of the device, which has four registers
+var elfcodeSet = elfcode.NewSet(4)
 
+// process will apply a named opcode with the arguments of a numbered opcode to the given registers.
+// False is returned if the arguments are not valid for the named opcode.
+func process(name string, o uOpcode, r registers) (registers, bool) {
+	inst, err := elfcodeSet.Instruction(name, o.a, o.b, o.c)
+	if err != nil {
+		return r, false
 	}
-}
-
-// Names of all opcodes
-var opcodeNames = []string{
-	"addr",
-	"addi",
-	"mulr",
-	"muli",
-	"banr",
-	"bani",
-	"borr",
-	"bori",
-	"setr",
-	"seti",
-	"gtir",
-	"gtri",
-	"gtrr",
-	"eqir",
-	"eqri",
-	"eqrr",
+	vm := elfcode.New(elfcodeSet, &elfcode.Program{IP: -1, Instructions: []machine.Instruction{inst}})
+	copy(vm.Registers, r[:])
+	if err := vm.Run(); err != nil {
+		return r, false
+	}
+	copy(r[:], vm.Registers)
+	return r, true
 }
 
 // sample holds before and after states of registers after an unnamed opcode is appled
@@ -153,22 +70,8 @@ func part1(samples []sample, threshold int) {
 	total := 0
 	for _, s := range samples {
 		count := 0
-		for _, name := range opcodeNames {
-			var r registers
-			for i, v := range s.before {
-				r[i] = v
-			}
-
-			o := Opcode{
-				name: name,
-				a:    s.opcode.a,
-				b:    s.opcode.b,
-				c:    s.opcode.c,
-			}
-
-			o.process(&r)
-
-			if r == s.after {
+		for _, name := range elfcode.OpNames {
+			if r, ok := process(name, s.opcode, s.before); ok && r == s.after {
 				count += 1
 			}
 		}
@@ -184,8 +87,8 @@ func part1(samples []sample, threshold int) {
 // solve reduces the list of possible opcodes to the only viable mapping
 func solve(opcodeNumbers map[int][]string) map[int]string {
 	// Create a slice of the available opcode names
-	availableOpcodeNames := make([]string, len(opcodeNames))
-	copy(availableOpcodeNames, opcodeNames)
+	availableOpcodeNames := make([]string, len(elfcode.OpNames))
+	copy(availableOpcodeNames, elfcode.OpNames)
 	solution := map[int]string{}
 
 	for len(availableOpcodeNames) > 0 {
@@ -224,22 +127,8 @@ func part2(samples []sample, program []uOpcode) {
 	for _, s := range samples {
 		// Find all possible opcodes for this sample
 		possibleOpcodes := []string{}
-		for _, name := range opcodeNames {
-			var r registers
-			for i, v := range s.before {
-				r[i] = v
-			}
-
-			o := Opcode{
-				name: name,
-				a:    s.opcode.a,
-				b:    s.opcode.b,
-				c:    s.opcode.c,
-			}
-
-			o.process(&r)
-
-			if r == s.after {
+		for _, name := range elfcode.OpNames {
+			if r, ok := process(name, s.opcode, s.before); ok && r == s.after {
 				possibleOpcodes = append(possibleOpcodes, name)
 			}
 		}
@@ -262,17 +151,19 @@ func part2(samples []sample, program []uOpcode) {
 
 	solved := solve(opcodeNumbers)
 
-	r := registers{}
-	for _, uop := range program {
-		op := Opcode{
-			name: solved[uop.number],
-			a:    uop.a,
-			b:    uop.b,
-			c:    uop.c,
+	instructions := make([]machine.Instruction, len(program))
+	for i, uop := range program {
+		inst, err := elfcodeSet.Instruction(solved[uop.number], uop.a, uop.b, uop.c)
+		if err != nil {
+			log.Fatal(err)
 		}
-		op.process(&r)
+		instructions[i] = inst
+	}
+	vm := elfcode.New(elfcodeSet, &elfcode.Program{IP: -1, Instructions: instructions})
+	if err := vm.Run(); err != nil {
+		log.Fatal(err)
 	}
-	fmt.Println("Part 2:", r[0])
+	fmt.Println("Part 2:", vm.Registers[0])
 }
 
 // parseData reads the input text file and returns data structures
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/animate"
 )
 
 // Define constants to represent elements in the reservoir
@@ -220,6 +222,7 @@ func isContained(reservoir [][]rune, x, y int) bool {
 
 // simulate models the flow of water in a reservoir region from the source
 func simulate(reservoir [][]rune) {
+	animate.Frame(reservoir)
 	for i := 0; i < len(reservoir)-1; {
 		jump := 1
 		for j := 0; j < len(reservoir[i]); j++ {
@@ -233,6 +236,7 @@ func simulate(reservoir [][]rune) {
 			}
 		}
 		i += jump
+		animate.Frame(reservoir)
 	}
 }
 
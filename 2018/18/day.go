@@ -3,9 +3,12 @@ package day18
 
 import (
 	"fmt"
+	"image/color"
 	"io/ioutil"
 	"log"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/animate"
 )
 
 // Define constants to represent elements in the lumber collection area
@@ -104,6 +107,13 @@ func tick(region [][]rune) [][]rune {
 	return newRegion
 }
 
+// palette holds the colours of the lumber collection area when animated
+var palette = animate.Palette{
+	openGround: color.RGBA{R: 0xc0, G: 0xa0, B: 0x40, A: 0xff},
+	trees:      color.RGBA{R: 0x20, G: 0xa0, B: 0x20, A: 0xff},
+	lumberyard: color.RGBA{R: 0x60, G: 0x40, B: 0x20, A: 0xff},
+}
+
 // printRegion prints the lumber collection area
 func printRegion(region [][]rune) {
 	ANSIGrey := "\033[30m\033[40m"
@@ -150,11 +160,12 @@ func resourceValue(region [][]rune) int {
 
 func Run(inputFile string) {
 	region := parseData(inputFile)
-	//	printRegion(region)
+	animate.UsePalette(palette)
+	animate.Frame(region)
 	part1Time := 10
 	for i := 1; i <= part1Time; i++ {
 		region = tick(region)
-		//		printRegion(region)
+		animate.Frame(region)
 	}
 	fmt.Println("Part 1: Resource value after", part1Time, "minutes is", resourceValue(region))
 
@@ -162,7 +173,7 @@ func Run(inputFile string) {
 	resourceValuesSeen := map[int]int{}
 	for i := part1Time + 1; i <= part2Time; i++ {
 		region = tick(region)
-		//		printRegion(region)
+		animate.Frame(region)
 		rv := resourceValue(region)
 		if lastSeen, ok := resourceValuesSeen[rv]; ok {
 			// Hopefully the region has reached a stable cycle
@@ -0,0 +1,144 @@
+// Advent of Code 2018 - Day 19
+package day19
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"slices"
+
+	"github.com/maze-mapper/advent-of-code/2018/elfcode"
+)
+
+// elfcodeSet is the instruction set of the device, which has six registers
+var elfcodeSet = elfcode.NewSet(6)
+
+// divisorLoop is the hot inner loop of the program, which tests every pair of factors against a target number
+type divisorLoop struct {
+	// instructions are the indices of the instructions in the loop
+	instructions map[int]bool
+	// target is the register holding the number whose divisors are summed
+	target int
+}
+
+// findLoop runs the program to completion with register 0 starting at zero, profiling it to find its hot inner loop.
+// The loop is made of the most run instructions and its target is the register which it compares against without
+// writing. The final value of register 0 is also returned.
+func findLoop(program *elfcode.Program) (divisorLoop, int, error) {
+	vm := elfcode.New(elfcodeSet, program)
+	vm.EnableProfiling()
+	if err := vm.Run(); err != nil {
+		return divisorLoop{}, 0, err
+	}
+	counts := vm.Profile()
+	most := slices.Max(counts)
+	loop := divisorLoop{instructions: map[int]bool{}, target: -1}
+	written := map[int]bool{}
+	for i, count := range counts {
+		if count == most {
+			loop.instructions[i] = true
+			// Every elfcode instruction writes to its third argument
+			written[program.Instructions[i].Args[2]] = true
+		}
+	}
+	for i := range loop.instructions {
+		inst := program.Instructions[i]
+		if inst.Op.Name != "eqrr" && inst.Op.Name != "gtrr" {
+			continue
+		}
+		for _, r := range inst.Args[:2] {
+			if !written[r] {
+				loop.target = r
+			}
+		}
+	}
+	if loop.target < 0 {
+		return divisorLoop{}, 0, fmt.Errorf("no register is compared against in the loop of %d instructions", len(loop.instructions))
+	}
+	return loop, vm.Registers[0], nil
+}
+
+// findTarget runs the setup part of the program with the given initial value of register 0 until the instruction
+// pointer first enters the loop, and returns the number whose divisors are summed by the loop
+func findTarget(program *elfcode.Program, loop divisorLoop, r0 int) (int, error) {
+	vm := elfcode.New(elfcodeSet, program)
+	vm.Registers[0] = r0
+	for !loop.instructions[vm.PC] {
+		ok, err := vm.Step()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, fmt.Errorf("program halted during setup")
+		}
+	}
+	return vm.Registers[loop.target], nil
+}
+
+// sumDivisors returns the sum of all divisors of n
+func sumDivisors(n int) int {
+	sum := 0
+	for i := 1; i*i <= n; i++ {
+		if n%i == 0 {
+			sum += i
+			if i*i != n {
+				sum += n / i
+			}
+		}
+	}
+	return sum
+}
+
+func part1(program *elfcode.Program) (int, error) {
+	loop, ans, err := findLoop(program)
+	if err != nil {
+		return 0, err
+	}
+
+	// Check that the program sums divisors so the same shortcut may be used for part 2
+	target, err := findTarget(program, loop, 0)
+	if err != nil {
+		return 0, err
+	}
+	if sumDivisors(target) != ans {
+		return 0, fmt.Errorf("program does not sum the divisors of %d", target)
+	}
+	return ans, nil
+}
+
+// part2 replaces the program's divisor sum loop, which would run for too long with register 0 starting at 1, with a
+// native sum of the divisors of its target number
+func part2(program *elfcode.Program) (int, error) {
+	loop, _, err := findLoop(program)
+	if err != nil {
+		return 0, err
+	}
+	target, err := findTarget(program, loop, 1)
+	if err != nil {
+		return 0, err
+	}
+	return sumDivisors(target), nil
+}
+
+func Run(inputFile string) {
+	data, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	program, err := elfcode.Parse(elfcodeSet, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p1, err := part1(program)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Part 1:", p1)
+
+	p2, err := part2(program)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Part 2:", p2)
+}
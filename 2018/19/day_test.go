@@ -0,0 +1,87 @@
+package day19
+
+import (
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/2018/elfcode"
+)
+
+// divisorProgram sums the divisors of 12, or of 360 when register 0 starts at 1.
+// The setup at the end sets the target in register 2 and jumps back to the loops over pairs of factors.
+var divisorProgram = []byte(`#ip 4
+addi 4 16 4
+seti 1 0 1
+seti 1 0 5
+mulr 1 5 3
+eqrr 3 2 3
+addr 3 4 4
+addi 4 1 4
+addr 1 0 0
+addi 5 1 5
+gtrr 5 2 3
+addr 4 3 4
+seti 2 0 4
+addi 1 1 1
+gtrr 1 2 3
+addr 3 4 4
+seti 1 0 4
+mulr 4 4 4
+seti 12 0 2
+addr 4 0 4
+seti 0 0 4
+seti 360 0 2
+seti 0 0 0
+seti 0 0 4`)
+
+func TestPart1(t *testing.T) {
+	program, err := elfcode.Parse(elfcodeSet, divisorProgram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 28
+	got, err := part1(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("part1() = %d, want %d", got, want)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	program, err := elfcode.Parse(elfcodeSet, divisorProgram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 1170
+	got, err := part2(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("part2() = %d, want %d", got, want)
+	}
+}
+
+func TestFindTarget(t *testing.T) {
+	program, err := elfcode.Parse(elfcodeSet, divisorProgram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loop, _, err := findLoop(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loop.target != 2 {
+		t.Errorf("loop target register = %d, want 2", loop.target)
+	}
+	for r0, want := range map[int]int{0: 12, 1: 360} {
+		got, err := findTarget(program, loop, r0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("findTarget(%d) = %d, want %d", r0, got, want)
+		}
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 // parseData reads the input text file and returns the cave depth and target coordinates
@@ -65,47 +69,44 @@ const (
 	allTools // Total number of tool options
 )
 
-// printRoute prints the route chosen through the cave
-func (cave *Cave) printRoute(path []nodeState) {
-	ANSIGreen := "\033[30m\033[42m"
-	ANSIYellow := "\033[30m\033[43m"
-	ANSIBlue := "\033[30m\033[44m"
-	ANSIClear := "\033[0m"
-
-	maxX := 0
-	maxY := 0
-	route := map[coord]int{}
-	for _, v := range path {
-		if v.c[0] > maxX {
-			maxX = v.c[0]
-		}
-		if v.c[1] > maxY {
-			maxY = v.c[1]
+// toolMarks are drawn on the route where a tool is equipped
+var toolMarks = map[int]rune{
+	noTools:      'N',
+	torch:        'T',
+	climbingGear: 'C',
+}
+
+// drawRoute draws the route chosen through the cave, with arrows for each move and the tool equipped where it is
+// changed
+func (cave *Cave) drawRoute(route []nodeState) {
+	maxX := cave.target[0]
+	maxY := cave.target[1]
+	path := make([]coordinates.Coord, len(route))
+	for i, v := range route {
+		maxX = max(maxX, v.c[0])
+		maxY = max(maxY, v.c[1])
+		path[i] = coordinates.Coord{X: v.c[0], Y: v.c[1]}
+	}
+
+	grid := make([][]rune, maxY+1)
+	for y := range grid {
+		grid[y] = make([]rune, maxX+1)
+		for x := range grid[y] {
+			grid[y][x] = cave.regionType(coord{x, y})
 		}
-		route[v.c] = v.tool
 	}
+	grid[0][0] = mouth
+	grid[cave.target[1]][cave.target[0]] = target
 
-	for i := 0; i <= maxY; i++ {
-		for j := 0; j <= maxX; j++ {
-			c := coord{j, i}
-			if t, ok := route[c]; ok {
-				switch t {
-				case noTools:
-					fmt.Print(ANSIGreen)
-				case torch:
-					fmt.Print(ANSIYellow)
-				case climbingGear:
-					fmt.Print(ANSIBlue)
-				}
-				fmt.Print(string(cave.regionType(c)))
-				fmt.Print(ANSIClear)
-			} else {
-				fmt.Print(string(cave.regionType(c)))
-			}
+	marks := overlay.Arrows(path)
+	for i := 1; i < len(route); i++ {
+		if route[i].tool != route[i-1].tool {
+			marks[path[i]] = toolMarks[route[i].tool]
 		}
-		fmt.Print("\n")
 	}
-	fmt.Print("\n")
+	if err := overlay.Draw("part2", grid, marks); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // coord holds a coordinate (x, y)
@@ -347,8 +348,9 @@ func (cave *Cave) getNextMoves(node *Node) []*Node {
 	return nextMoves
 }
 
-// traverse finds the shortest path from the cave mouth to the target using an A* approach
-func (cave *Cave) traverse() {
+// traverse finds the shortest path from the cave mouth to the target using an A* approach, returning the time taken
+// and the route from the mouth to the target
+func (cave *Cave) traverse() (int, []nodeState) {
 	// Initialise priority queue
 	pq := make(PriorityQueue, 1)
 	mouthCoord := coord{0, 0}
@@ -386,10 +388,8 @@ func (cave *Cave) traverse() {
 				}
 				p = q
 			}
-			cave.printRoute(route)
-
-			fmt.Println("Part 2: Time taken to reach target at", cave.target, "is", node.time, "minutes")
-			break
+			slices.Reverse(route)
+			return node.time, route
 		}
 
 		// Find all possible next moves and add any unexplored ones to the priority queue
@@ -403,11 +403,17 @@ func (cave *Cave) traverse() {
 			}
 		}
 	}
+	log.Fatal("No route to the target")
+	return 0, nil
 }
 
 func Run(inputFile string) {
 	cave := parseData(inputFile)
 	risk := cave.riskLevel()
 	fmt.Println("Part 1: The risk level is", risk)
-	cave.traverse()
+	time, route := cave.traverse()
+	fmt.Println("Part 2: Time taken to reach target at", cave.target, "is", time, "minutes")
+	if overlay.Enabled() {
+		cave.drawRoute(route)
+	}
 }
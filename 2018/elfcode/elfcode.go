@@ -0,0 +1,196 @@
+// Elfcode device
+package elfcode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maze-mapper/advent-of-code/machine"
+)
+
+// OpNames are the names of all elfcode operations
+var OpNames = []string{
+	"addr",
+	"addi",
+	"mulr",
+	"muli",
+	"banr",
+	"bani",
+	"borr",
+	"bori",
+	"setr",
+	"seti",
+	"gtir",
+	"gtri",
+	"gtrr",
+	"eqir",
+	"eqri",
+	"eqrr",
+}
+
+// op returns an operation which stores the result of f in register C, with A and B given as register or immediate
+// operands
+func op(name string, a, b machine.OperandKind, f func(a, b int) int) machine.Op {
+	return machine.Op{
+		Name:     name,
+		Operands: []machine.OperandKind{a, b, machine.Register},
+		Exec: func(m *machine.Machine, args []int) error {
+			x, y := args[0], args[1]
+			if a == machine.Register {
+				x = m.Registers[x]
+			}
+			if b == machine.Register {
+				y = m.Registers[y]
+			}
+			m.Registers[args[2]] = f(x, y)
+			return nil
+		},
+	}
+}
+
+// boolToInt returns 1 for true and 0 for false
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// NewSet returns the elfcode instruction set for a device with the given number of registers, which are named by
+// their index
+func NewSet(registers int) *machine.Set {
+	names := make([]string, registers)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+	}
+	r, i := machine.Register, machine.Immediate
+	return machine.MustNewSet(
+		names,
+		op("addr", r, r, func(a, b int) int { return a + b }),
+		op("addi", r, i, func(a, b int) int { return a + b }),
+		op("mulr", r, r, func(a, b int) int { return a * b }),
+		op("muli", r, i, func(a, b int) int { return a * b }),
+		op("banr", r, r, func(a, b int) int { return a & b }),
+		op("bani", r, i, func(a, b int) int { return a & b }),
+		op("borr", r, r, func(a, b int) int { return a | b }),
+		op("bori", r, i, func(a, b int) int { return a | b }),
+		op("setr", r, i, func(a, b int) int { return a }),
+		op("seti", i, i, func(a, b int) int { return a }),
+		op("gtir", i, r, func(a, b int) int { return boolToInt(a > b) }),
+		op("gtri", r, i, func(a, b int) int { return boolToInt(a > b) }),
+		op("gtrr", r, r, func(a, b int) int { return boolToInt(a > b) }),
+		op("eqir", i, r, func(a, b int) int { return boolToInt(a == b) }),
+		op("eqri", r, i, func(a, b int) int { return boolToInt(a == b) }),
+		op("eqrr", r, r, func(a, b int) int { return boolToInt(a == b) }),
+	)
+}
+
+// Program is an elfcode program which may have the instruction pointer bound to a register
+type Program struct {
+	// IP is the register bound to the instruction pointer, or -1 if it is not bound
+	IP           int
+	Instructions []machine.Instruction
+}
+
+// Parse reads a program for an instruction set, which may start with an "#ip N" line to bind the instruction pointer
+// to register N
+func Parse(set *machine.Set, data []byte) (*Program, error) {
+	lines := strings.Split(
+		strings.TrimSuffix(string(data), "\n"), "\n",
+	)
+	program := &Program{IP: -1}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#ip ") {
+			if i != 0 {
+				return nil, fmt.Errorf("line %d: #ip must be the first line", i+1)
+			}
+			ip, err := strconv.Atoi(strings.TrimPrefix(line, "#ip "))
+			if err != nil || ip < 0 || ip >= len(set.Registers()) {
+				return nil, fmt.Errorf("line %d: invalid instruction pointer register %q", i+1, line)
+			}
+			program.IP = ip
+			continue
+		}
+		inst, err := set.ParseInstruction(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		program.Instructions = append(program.Instructions, inst)
+	}
+	return program, nil
+}
+
+// VM runs an elfcode program.
+// The BeforeStep and AfterStep hooks of the machine are used to bind the instruction pointer and count instructions.
+type VM struct {
+	*machine.Machine
+	set     *machine.Set
+	program *Program
+	counts  []int
+}
+
+// New creates a VM to run a program with every register set to zero
+func New(set *machine.Set, program *Program) *VM {
+	vm := &VM{
+		Machine: machine.New(set, program.Instructions),
+		set:     set,
+		program: program,
+	}
+	vm.BeforeStep = func(m *machine.Machine, inst machine.Instruction) error {
+		if vm.counts != nil {
+			vm.counts[m.PC]++
+		}
+		if program.IP >= 0 {
+			m.Registers[program.IP] = m.PC
+		}
+		return nil
+	}
+	vm.AfterStep = func(m *machine.Machine, inst machine.Instruction) error {
+		if program.IP >= 0 {
+			m.Goto(m.Registers[program.IP] + 1)
+		}
+		return nil
+	}
+	return vm
+}
+
+// EnableProfiling starts counting how many times each instruction is run
+func (vm *VM) EnableProfiling() {
+	if vm.counts == nil {
+		vm.counts = make([]int, len(vm.program.Instructions))
+	}
+}
+
+// Profile returns how many times each instruction has been run since profiling was enabled
+func (vm *VM) Profile() []int {
+	return vm.counts
+}
+
+// WriteProfile writes the n most run instructions, most run first, with their share of all instructions run
+func (vm *VM) WriteProfile(w io.Writer, n int) error {
+	order := make([]int, len(vm.counts))
+	total := 0
+	for i, c := range vm.counts {
+		order[i] = i
+		total += c
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return vm.counts[order[i]] > vm.counts[order[j]]
+	})
+
+	for _, i := range order[:min(n, len(order))] {
+		if vm.counts[i] == 0 {
+			break
+		}
+		_, err := fmt.Fprintf(w, "%3d: %-16s %12d %6.2f%%\n",
+			i, vm.set.Format(vm.program.Instructions[i]), vm.counts[i], 100*float64(vm.counts[i])/float64(total),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package elfcode
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/machine"
+)
+
+var example = []byte(`#ip 0
+seti 5 0 1
+seti 6 0 2
+addi 0 1 0
+addr 1 2 3
+setr 1 0 0
+seti 8 0 4
+seti 9 0 5`)
+
+func TestRun(t *testing.T) {
+	set := NewSet(6)
+	program, err := Parse(set, example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if program.IP != 0 {
+		t.Errorf("program.IP = %d, want 0", program.IP)
+	}
+
+	vm := New(set, program)
+	vm.EnableProfiling()
+	if err := vm.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := []int{6, 5, 6, 0, 0, 9}
+	if !slices.Equal(vm.Registers, want) {
+		t.Errorf("registers = %v, want %v", vm.Registers, want)
+	}
+	wantCounts := []int{1, 1, 1, 0, 1, 0, 1}
+	if !slices.Equal(vm.Profile(), wantCounts) {
+		t.Errorf("profile = %v, want %v", vm.Profile(), wantCounts)
+	}
+
+	var sb strings.Builder
+	if err := vm.WriteProfile(&sb, 2); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"); len(lines) != 2 {
+		t.Errorf("WriteProfile(2) wrote %d lines, want 2", len(lines))
+	}
+}
+
+func TestUnbound(t *testing.T) {
+	set := NewSet(4)
+	program, err := Parse(set, []byte("seti 7 0 1\naddi 1 3 2\nmulr 1 2 0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if program.IP != -1 {
+		t.Errorf("program.IP = %d, want -1", program.IP)
+	}
+	vm := New(set, program)
+	if err := vm.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if vm.Registers[0] != 70 {
+		t.Errorf("register 0 = %d, want 70", vm.Registers[0])
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	set := NewSet(6)
+	program, err := Parse(set, []byte("#ip 1\nseti -1 0 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := New(set, program)
+	vm.MaxSteps = 50
+	if err := vm.Run(); !errors.Is(err, machine.ErrStepLimit) {
+		t.Errorf("Run() = %v, want %v", err, machine.ErrStepLimit)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	set := NewSet(4)
+	tests := []string{
+		"#ip 4\nseti 0 0 1",
+		"seti 0 0 1\n#ip 0",
+		"addr 0 0 4",
+		"jmp 1 2 3",
+	}
+	for _, tc := range tests {
+		if _, err := Parse(set, []byte(tc)); err == nil {
+			t.Errorf("Parse(%q) returned no error", tc)
+		}
+	}
+}
@@ -22,7 +22,7 @@ import (
 	"github.com/maze-mapper/advent-of-code/2018/16"
 	"github.com/maze-mapper/advent-of-code/2018/17"
 	"github.com/maze-mapper/advent-of-code/2018/18"
-//	"github.com/maze-mapper/advent-of-code/2018/19"
+	"github.com/maze-mapper/advent-of-code/2018/19"
 //	"github.com/maze-mapper/advent-of-code/2018/20"
 //	"github.com/maze-mapper/advent-of-code/2018/21"
 	"github.com/maze-mapper/advent-of-code/2018/22"
@@ -70,8 +70,8 @@ func Run(day, inputFile string) {
 		f = day17.Run
 	case "18":
 		f = day18.Run
-//	case "19":
-//		f = day19.Run
+	case "19":
+		f = day19.Run
 //	case "20":
 //		f = day20.Run
 //	case "21":
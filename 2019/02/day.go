@@ -19,7 +19,9 @@ func runGravityAssist(program []int, noun, verb int) int {
 	program[2] = verb
 
 	computer := intcode.New(program)
-	computer.Run()
+	if err := computer.Run(); err != nil {
+		log.Fatal(err)
+	}
 	return computer.Program()[0]
 }
 
@@ -17,7 +17,11 @@ func runProgram(program []int, input int) []int {
 	computer.SetChanIn(chanIn)
 	computer.SetChanOut(chanOut)
 
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 	chanIn <- input
 
 	outputs := []int{}
@@ -46,37 +46,28 @@ func generatePermutations(k int, s []int) [][]int {
 func runAmplifierPrograms(program, phaseSettings []int) int {
 	numAmplifiers := len(phaseSettings)
 
-	// Set up input channels
-	inputChannels := make([]chan int, numAmplifiers)
+	// Each amplifier first takes the phase setting and then the signals
+	computers := make([]*intcode.Computer, numAmplifiers)
 	for i, phase := range phaseSettings {
-		// Each input first takes the phase setting and then the signal
-		// So create a buffered channel with length 2
-		inputChannels[i] = make(chan int, 2)
-		inputChannels[i] <- phase
+		computers[i] = intcode.New(program)
+		computers[i].Input(phase)
 	}
 	// Pass in input signal to first amplifier
-	inputChannels[0] <- 0
-
-	// Set up and run amplifiers with connected channels
-	outputChannel := make(chan int)
-	for i := 0; i < len(inputChannels); i++ {
-		computer := intcode.New(program)
-		computer.SetChanIn(inputChannels[i])
-		if i < len(phaseSettings)-1 {
-			computer.SetChanOut(inputChannels[i+1])
-		} else {
-			computer.SetChanOut(outputChannel)
-		}
-		go computer.Run()
+	computers[0].Input(0)
+
+	// Amplifiers are connected in a loop so the last output is also sent back to the first amplifier
+	network := intcode.NewNetwork(computers, intcode.RingRouter{Size: numAmplifiers}, nil)
+	if err := network.Run(); err != nil {
+		log.Fatal(err)
 	}
 
-	// Pass output signals back in to the first input channel and return the last signal received
+	// Return the last signal sent by the last amplifier
 	var output int
-	for out := range outputChannel {
-		inputChannels[0] <- out
-		output = out
+	for _, p := range network.Log() {
+		if p.Source == numAmplifiers-1 {
+			output = p.Data[0]
+		}
 	}
-
 	return output
 }
 
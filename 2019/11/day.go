@@ -7,6 +7,8 @@ import (
 	"log"
 
 	"github.com/maze-mapper/advent-of-code/2019/intcode"
+	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/ocr"
 )
 
 // Coord is a Cartesian coordinate
@@ -77,7 +79,11 @@ func runRobot(program []int, startColour int) map[Coord]int {
 	computer.SetChanIn(chanIn)
 	computer.SetChanOut(chanOut)
 
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 	chanIn <- startColour
 
 	outputCount := 0
@@ -105,44 +111,15 @@ func runRobot(program []int, startColour int) map[Coord]int {
 	return painted
 }
 
-// findCoordinateRange returns the minimum and maximum values of x and y for a set of coordinates
-func findCoordinateRange(painted map[Coord]int) (int, int, int, int) {
-	// Robot starts at the origin
-	var minX, minY, maxX, maxY int
-	for c := range painted {
-		if c.x < minX {
-			minX = c.x
-		}
-		if c.y < minY {
-			minY = c.y
-		}
-		if c.x > maxX {
-			maxX = c.x
-		}
-		if c.y > maxY {
-			maxY = c.y
-		}
-	}
-	return minX, minY, maxX, maxY
-}
-
-// printIdentifier prints the identifier painted by the robot
-func printIdentifier(painted map[Coord]int) {
-	minX, minY, maxX, maxY := findCoordinateRange(painted)
-
-	for i := maxY; i >= minY; i-- {
-		for j := minX; j <= maxX; j++ {
-			c := Coord{x: j, y: i}
-			colour := painted[c]
-			switch colour {
-			case black:
-				fmt.Print(".")
-			case white:
-				fmt.Print("#")
-			}
+// identifier returns the identifier painted by the robot as a grid of white panels with the top row first
+func identifier(painted map[Coord]int) [][]bool {
+	panels := map[coordinates.Coord]bool{}
+	for c, colour := range painted {
+		if colour == white {
+			panels[coordinates.Coord{X: c.x, Y: -c.y}] = true
 		}
-		fmt.Print("\n")
 	}
+	return ocr.FromCoords(panels)
 }
 
 func part1(program []int) int {
@@ -150,9 +127,9 @@ func part1(program []int) int {
 	return len(painted)
 }
 
-func part2(program []int) {
+func part2(program []int) [][]bool {
 	painted := runRobot(program, white)
-	printIdentifier(painted)
+	return identifier(painted)
 }
 
 func Run(inputFile string) {
@@ -165,6 +142,6 @@ func Run(inputFile string) {
 	p1 := part1(program)
 	fmt.Println("Part 1:", p1)
 
-	fmt.Println("Part 2:")
-	part2(program)
+	p2 := part2(program)
+	fmt.Println("Part 2:", ocr.Answer(p2))
 }
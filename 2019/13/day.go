@@ -83,7 +83,11 @@ func runArcade(program []int) (map[Coord]int, int) {
 	computer.SetChanIn(chanIn)
 	computer.SetChanOut(chanOut)
 
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	var paddlePlaced, ballPlaced bool
 	var x, y, score, outputCount int
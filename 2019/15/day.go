@@ -161,7 +161,11 @@ func exploreArea(program []int) map[coordinates.Coord]int {
 	computer.SetChanOut(chanOut)
 
 	// Bad practice: this will not terminate
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	droid := NewDroid()
 	droid.DFS(&chanIn, &chanOut)
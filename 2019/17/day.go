@@ -13,18 +13,22 @@ import (
 )
 
 // runProgram runs the given ASCII program and returns the output
-func runProgram(program []int, input []byte) []int {
+func runProgram(program []int, input []int) []int {
 	computer := intcode.New(program)
 	chanIn := make(chan int)
 	chanOut := make(chan int)
 	computer.SetChanIn(chanIn)
 	computer.SetChanOut(chanOut)
 
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	go func() {
 		for _, in := range input {
-			chanIn <- int(in)
+			chanIn <- in
 		}
 	}()
 
@@ -359,17 +363,10 @@ func compress(route []string) (string, string, string, string) {
 	return "", "", "", ""
 }
 
-// makeInput converts the string movement routine and functions in to a byte slice
-func makeInput(m, a, b, c string) []byte {
-	var builder strings.Builder
-	components := []string{m, a, b, c}
-	for _, component := range components {
-		builder.WriteString(component)
-		builder.WriteString("\n")
-	}
+// makeInput converts the string movement routine and functions in to ASCII input
+func makeInput(m, a, b, c string) []int {
 	// Choose "n" for continuous video feed
-	builder.WriteString("n\n")
-	return []byte(builder.String())
+	return intcode.EncodeASCII(m, a, b, c, "n")
 }
 
 func part2(data [][]byte, program []int) int {
@@ -391,17 +388,15 @@ func Run(inputFile string) {
 	}
 	program := intcode.ReadProgram(data)
 
-	ioutput := runProgram(program, []byte{})
-
-	output := make([]byte, len(ioutput))
-	for i, o := range ioutput {
-		// Unsafe if o overflows byte
-		output[i] = byte(o)
+	// The camera view is only ASCII, so any other value means the program did not draw it
+	text, other := intcode.DecodeASCII(runProgram(program, nil))
+	if len(other) > 0 {
+		log.Fatalf("camera output has non-ASCII values %v", other)
 	}
 
-	fmt.Print(string(output))
+	fmt.Print(text)
 
-	lines := splitOutput(output)
+	lines := splitOutput([]byte(text))
 
 	p1 := part1(lines)
 	fmt.Println("Part 1:", p1)
@@ -23,7 +23,11 @@ func checkPoint(program []int, x, y int) int {
 	computer.SetChanIn(chanIn)
 	computer.SetChanOut(chanOut)
 
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	chanIn <- x
 	chanIn <- y
@@ -6,22 +6,25 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"strings"
 )
 
 // runProgram runs the given program with ASCII input and returns the output
-func runProgram(program []int, input []byte) []int {
+func runProgram(program []int, input []int) []int {
 	computer := intcode.New(program)
 	chanIn := make(chan int)
 	chanOut := make(chan int)
 	computer.SetChanIn(chanIn)
 	computer.SetChanOut(chanOut)
 
-	go computer.Run()
+	go func() {
+		if err := computer.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	go func() {
 		for _, in := range input {
-			chanIn <- int(in)
+			chanIn <- in
 		}
 	}()
 
@@ -33,29 +36,12 @@ func runProgram(program []int, input []byte) []int {
 	return output
 }
 
-// makeInput converts a slice of strings in to a byte slice
-func makeInput(parts []string) []byte {
+// makeInput converts springscript instructions in to ASCII input
+func makeInput(parts []string) []int {
 	if len(parts) > 15 {
 		log.Fatal("Too many springscript instructions")
 	}
-
-	var builder strings.Builder
-	for _, part := range parts {
-		builder.WriteString(part)
-		builder.WriteString("\n")
-	}
-
-	return []byte(builder.String())
-}
-
-// printOutput will print the output from the Intcode computer
-func printOutput(output []int) {
-	b := make([]byte, len(output))
-	for i, o := range output {
-		// Unsafe if o overflows byte
-		b[i] = byte(o)
-	}
-	fmt.Println(string(b))
+	return intcode.EncodeASCII(parts...)
 }
 
 // runSpringdroid runs the springscript instructions and returns the hull damage
@@ -63,15 +49,13 @@ func runSpringdroid(program []int, instructions []string) int {
 	input := makeInput(instructions)
 	output := runProgram(program, input)
 
-	var damage int
-	lastIndex := len(output) - 1
-	if output[lastIndex] > 256 {
-		damage = output[lastIndex]
-		output = output[:lastIndex]
-	} else {
-		printOutput(output)
+	// The hull damage is the only output which is not an ASCII character, otherwise the droid fell in to space
+	text, other := intcode.DecodeASCII(output)
+	if len(other) == 0 {
+		fmt.Println(text)
+		return 0
 	}
-	return damage
+	return other[len(other)-1]
 }
 
 func part1(program []int) int {
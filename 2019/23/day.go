@@ -5,151 +5,89 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"sync"
 
 	"github.com/maze-mapper/advent-of-code/2019/intcode"
 )
 
-// packet holds the data for a packet sent between computers
-type packet struct {
-	x, y int
-}
+// natAddress is the network address of the NAT
+const natAddress = 255
 
-// setupComputers creates n Intcode computers along with their input and output channels
-func setupComputers(program []int, n int) ([]*intcode.Computer, []chan int, []chan int) {
-	inputChannels := make([]chan int, n)
-	outputChannels := make([]chan int, n)
+// setupNetwork creates a network of n Intcode computers which have been sent their network addresses
+func setupNetwork(program []int, n int, policy intcode.Policy) *intcode.Network {
 	computers := make([]*intcode.Computer, n)
-
 	for i := 0; i < n; i++ {
-		chanIn := make(chan int, 256)
-		chanOut := make(chan int, 256)
 		computer := intcode.New(program)
 		computer.SetNonBlocking(-1)
-		computer.SetChanIn(chanIn)
-		computer.SetChanOut(chanOut)
-
-		inputChannels[i] = chanIn
-		outputChannels[i] = chanOut
+		computer.Input(i)
 		computers[i] = computer
-
-		// Send network addres
-		chanIn <- i
 	}
+	return intcode.NewNetwork(computers, intcode.AddressedRouter{}, policy)
+}
 
-	return computers, inputChannels, outputChannels
+// firstPacket is a policy which stops the network at the first packet sent to the NAT
+type firstPacket struct {
+	y int
 }
 
-func runComputers(computers []*intcode.Computer, inputChannels, outputChannels []chan int, chanDone chan packet) {
-	n := len(computers)
+// Deliver implements intcode.Policy
+func (f *firstPacket) Deliver(p intcode.Packet) bool {
+	f.y = p.Data[1]
+	return true
+}
 
-	// Set up channels for packets to ensure that we do not interleave packets
-	packetInputChannels := make([]chan packet, len(inputChannels))
-	for i := 0; i < n; i++ {
-		idx := i
-		packetInputChannels[i] = make(chan packet, 128)
-		go func() {
-			for p := range packetInputChannels[idx] {
-				inputChannels[idx] <- p.x
-				inputChannels[idx] <- p.y
-			}
-		}()
-	}
+// Idle implements intcode.Policy
+func (f *firstPacket) Idle() ([]intcode.Packet, bool) {
+	return nil, false
+}
 
-	// Goroutines for sending and receiving packets
-	for i := 0; i < n; i++ {
-		chanOut := outputChannels[i]
-		go func() {
-			var o int
-			var addr, x, y int
-			for out := range chanOut {
-				switch o % 3 {
-				case 0:
-					addr = out
-				case 1:
-					x = out
-				case 2:
-					y = out
-					p := packet{x: x, y: y}
-					if addr == 255 {
-						chanDone <- p
-					} else {
-						packetInputChannels[addr] <- p
-					}
-				}
-				o += 1
-			}
-		}()
-	}
+// nat is a policy which remembers the last packet it received and sends it to address 0 when the network is idle.
+// The network is stopped when the same Y value is sent to address 0 twice in a row.
+type nat struct {
+	last     intcode.Packet
+	received bool
+	sentY    int
+	sent     bool
+	repeated bool
+}
 
-	// Start computers as close to the same time as possible
-	chanStart := make(chan struct{})
-	for i := 0; i < n; i++ {
-		computer := computers[i]
-		go func() {
-			<-chanStart
-			computer.Run()
-		}()
+// Deliver implements intcode.Policy
+func (n *nat) Deliver(p intcode.Packet) bool {
+	n.last = p
+	n.received = true
+	return false
+}
+
+// Idle implements intcode.Policy
+func (n *nat) Idle() ([]intcode.Packet, bool) {
+	if !n.received {
+		return nil, false
+	}
+	y := n.last.Data[1]
+	if n.sent && y == n.sentY {
+		n.repeated = true
+		return nil, true
 	}
-	close(chanStart)
+	n.sentY, n.sent = y, true
+	return []intcode.Packet{{Source: natAddress, Destination: 0, Data: n.last.Data}}, false
 }
 
 func part1(program []int) int {
-	computers, inputChannels, outputChannels := setupComputers(program, 50)
-	chanDone := make(chan packet, 255)
-	runComputers(computers, inputChannels, outputChannels, chanDone)
-	p := <-chanDone
-	return p.y
+	policy := &firstPacket{}
+	if err := setupNetwork(program, 50, policy).Run(); err != nil {
+		log.Fatal(err)
+	}
+	return policy.y
 }
 
 func part2(program []int) int {
-	var mutex = &sync.Mutex{}
-
-	var natPacket packet
-	var sendableNatPacket bool
-	chanNat := make(chan packet, 255)
-	go func() {
-		for p := range chanNat {
-			//			fmt.Println("Received NAT packet", p)
-			natPacket = p
-			mutex.Lock()
-			sendableNatPacket = true
-			mutex.Unlock()
-		}
-	}()
-
-	computers, inputChannels, outputChannels := setupComputers(program, 50)
-	runComputers(computers, inputChannels, outputChannels, chanNat)
-
-	var lastY int
-	for {
-		// Determine if all computers are idle
-		idle := true
-		for _, c := range computers {
-			// Consider idle if there have been a minimum number of reads with no input
-			if !c.IdleFor(1000) {
-				idle = false
-				break
-			}
-		}
-
-		if idle && sendableNatPacket {
-			sentPacket := natPacket
-			//			fmt.Println("Sending packet", sentPacket)
-			if sentPacket.y == lastY {
-				return sentPacket.y
-			} else {
-				lastY = sentPacket.y
-			}
-
-			mutex.Lock()
-			sendableNatPacket = false
-			mutex.Unlock()
-			inputChannels[0] <- sentPacket.x
-			inputChannels[0] <- sentPacket.y
-		}
+	policy := &nat{}
+	if err := setupNetwork(program, 50, policy).Run(); err != nil {
+		log.Fatal(err)
+	}
+	if !policy.repeated {
+		log.Fatal("Network stopped before the NAT repeated a value")
 	}
-	return 0
+	return policy.sentY
 }
 
 func Run(inputFile string) {
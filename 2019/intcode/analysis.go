@@ -0,0 +1,377 @@
+package intcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
+)
+
+// Instruction is a single decoded instruction in a program
+type Instruction struct {
+	Address int
+	Opcode  int
+	Modes   [3]int
+	Params  []int
+}
+
+// instructionLengths are the number of memory cells taken up by each instruction including its parameters
+var instructionLengths = map[int]int{
+	OpcodeAdd:                4,
+	OpcodeMultiply:           4,
+	OpcodeInput:              2,
+	OpcodeOutput:             2,
+	OpcodeJumpIfTrue:         3,
+	OpcodeJumpIfFalse:        3,
+	OpcodeLessThan:           4,
+	OpcodeEquals:             4,
+	OpcodeRelativeBaseOffset: 2,
+	OpcodeHalt:               1,
+}
+
+// mnemonics are the names used when disassembling instructions
+var mnemonics = map[int]string{
+	OpcodeAdd:                "add",
+	OpcodeMultiply:           "mul",
+	OpcodeInput:              "in",
+	OpcodeOutput:             "out",
+	OpcodeJumpIfTrue:         "jt",
+	OpcodeJumpIfFalse:        "jf",
+	OpcodeLessThan:           "lt",
+	OpcodeEquals:             "eq",
+	OpcodeRelativeBaseOffset: "arb",
+	OpcodeHalt:               "halt",
+}
+
+// decode returns the instruction at an address, or false if it is not a valid instruction
+func decode(program []int, address int) (Instruction, bool) {
+	if address < 0 || address >= len(program) || program[address] < 0 {
+		return Instruction{}, false
+	}
+	opcode := readOpcode(program[address])
+	length, ok := instructionLengths[opcode]
+	if !ok || address+length > len(program) {
+		return Instruction{}, false
+	}
+	inst := Instruction{
+		Address: address,
+		Opcode:  opcode,
+		Modes:   parameterModes(program[address] / 100),
+		Params:  program[address+1 : address+length],
+	}
+	for i := range inst.Params {
+		if inst.Modes[i] > ParameterModeRelative {
+			return Instruction{}, false
+		}
+	}
+	return inst, true
+}
+
+// Len returns the number of memory cells taken up by the instruction
+func (inst Instruction) Len() int {
+	return len(inst.Params) + 1
+}
+
+// writes returns whether the last parameter of the instruction is a destination which is written to
+func (inst Instruction) writes() bool {
+	switch inst.Opcode {
+	case OpcodeAdd, OpcodeMultiply, OpcodeInput, OpcodeLessThan, OpcodeEquals:
+		return true
+	}
+	return false
+}
+
+// immediate returns the value of the nth parameter if it is known without running the program
+func (inst Instruction) immediate(n int) (int, bool) {
+	return inst.Params[n], inst.Modes[n] == ParameterModeImmediate
+}
+
+// operand formats a parameter as #value for immediate, [address] for position and rb[offset] for relative mode
+func (inst Instruction) operand(n int) string {
+	switch inst.Modes[n] {
+	case ParameterModeImmediate:
+		return fmt.Sprintf("#%d", inst.Params[n])
+	case ParameterModeRelative:
+		return fmt.Sprintf("rb[%d]", inst.Params[n])
+	default:
+		return fmt.Sprintf("[%d]", inst.Params[n])
+	}
+}
+
+// String returns the disassembled instruction
+func (inst Instruction) String() string {
+	operands := make([]string, len(inst.Params))
+	for i := range inst.Params {
+		operands[i] = inst.operand(i)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d: %s %s", inst.Address, mnemonics[inst.Opcode], strings.Join(operands, ", ")))
+}
+
+// EdgeKind describes how control passes between basic blocks
+type EdgeKind int
+
+// Kinds of control flow edge
+const (
+	EdgeFallthrough EdgeKind = iota
+	EdgeJump
+	EdgeCall
+	EdgeReturnSite
+)
+
+// edgeStyles are the DOT attributes used to draw each kind of edge
+var edgeStyles = map[EdgeKind]dot.Attrs{
+	EdgeFallthrough: {"style": "solid"},
+	EdgeJump:        {"style": "solid", "color": "blue"},
+	EdgeCall:        {"style": "dashed", "color": "darkgreen", "label": "call"},
+	EdgeReturnSite:  {"style": "dotted", "label": "return"},
+}
+
+// Edge is a control flow edge to the basic block starting at an address
+type Edge struct {
+	To   int
+	Kind EdgeKind
+}
+
+// Block is a basic block, a run of instructions which is only entered at the first and only left after the last
+type Block struct {
+	Start        int
+	Instructions []Instruction
+	Successors   []Edge
+	// Return is set for blocks which end by jumping to an address held relative to the relative base
+	Return bool
+	// Indirect is set for blocks which end by jumping to an address which is not known statically
+	Indirect bool
+}
+
+// Write is an instruction writing to an address which is known statically
+type Write struct {
+	From, To int
+}
+
+// Graph is the control flow graph of a program
+type Graph struct {
+	Blocks []*Block
+	// SelfModifying holds writes to addresses which hold reachable instructions
+	SelfModifying []Write
+	// Invalid holds reachable addresses which do not hold a valid instruction
+	Invalid []int
+}
+
+// successor is a control flow edge from the instruction at an address
+type successor struct {
+	from int
+	edge Edge
+}
+
+// callReturn returns whether a jump is a call, which is recognised by an earlier instruction in the same straight line
+// of code storing the address following the jump relative to the relative base
+func callReturn(straight []Instruction, jump Instruction) bool {
+	ret := jump.Address + jump.Len()
+	for i := len(straight) - 1; i >= 0; i-- {
+		inst := straight[i]
+		if inst.Opcode != OpcodeAdd && inst.Opcode != OpcodeMultiply || inst.Modes[2] != ParameterModeRelative {
+			continue
+		}
+		a, okA := inst.immediate(0)
+		b, okB := inst.immediate(1)
+		if !okA || !okB {
+			continue
+		}
+		if inst.Opcode == OpcodeAdd && a+b == ret || inst.Opcode == OpcodeMultiply && a*b == ret {
+			return true
+		}
+	}
+	return false
+}
+
+// Analyse builds the control flow graph of the instructions reachable from address zero.
+// Jump targets are only followed when given as immediate parameters, jumps to addresses held relative to the
+// relative base are treated as returns from calls.
+func Analyse(program []int) *Graph {
+	graph := &Graph{}
+	instructions := map[int]Instruction{}
+	code := map[int]bool{}
+	leaders := map[int]bool{0: true}
+	edges := []successor{}
+	returns := map[int]bool{}
+	indirect := map[int]bool{}
+	writes := []Write{}
+	invalid := map[int]bool{}
+
+	// Follow straight lines of code from each address which may be jumped to
+	work := []int{0}
+	for len(work) > 0 {
+		address := work[len(work)-1]
+		work = work[:len(work)-1]
+		straight := []Instruction{}
+
+		for {
+			if _, ok := instructions[address]; ok || invalid[address] {
+				break
+			}
+			inst, ok := decode(program, address)
+			if !ok {
+				invalid[address] = true
+				graph.Invalid = append(graph.Invalid, address)
+				break
+			}
+			instructions[address] = inst
+			straight = append(straight, inst)
+			for i := address; i < address+inst.Len(); i++ {
+				code[i] = true
+			}
+			if inst.writes() && inst.Modes[len(inst.Params)-1] == ParameterModePosition {
+				writes = append(writes, Write{From: address, To: inst.Params[len(inst.Params)-1]})
+			}
+
+			next := address + inst.Len()
+			if inst.Opcode == OpcodeHalt {
+				break
+			}
+			if inst.Opcode != OpcodeJumpIfTrue && inst.Opcode != OpcodeJumpIfFalse {
+				address = next
+				continue
+			}
+
+			// Jumps end a basic block, determine if the condition and target are known statically
+			leaders[next] = true
+			cond, condKnown := inst.immediate(0)
+			always := condKnown && (cond != 0) == (inst.Opcode == OpcodeJumpIfTrue)
+			never := condKnown && !always
+			if !never {
+				if target, ok := inst.immediate(1); ok {
+					kind := EdgeJump
+					if callReturn(straight, inst) {
+						kind = EdgeCall
+						edges = append(edges, successor{from: address, edge: Edge{To: next, Kind: EdgeReturnSite}})
+						work = append(work, next)
+					}
+					leaders[target] = true
+					edges = append(edges, successor{from: address, edge: Edge{To: target, Kind: kind}})
+					work = append(work, target)
+				} else if inst.Modes[1] == ParameterModeRelative {
+					returns[address] = true
+				} else {
+					indirect[address] = true
+				}
+			}
+			if always {
+				break
+			}
+			edges = append(edges, successor{from: address, edge: Edge{To: next, Kind: EdgeFallthrough}})
+			address = next
+		}
+	}
+
+	// Split the decoded instructions in to basic blocks at each leader
+	addresses := make([]int, 0, len(instructions))
+	for address := range instructions {
+		addresses = append(addresses, address)
+	}
+	sort.Ints(addresses)
+	blockOf := map[int]*Block{}
+	var block *Block
+	for _, address := range addresses {
+		inst := instructions[address]
+		if block == nil || leaders[address] || block.last().Address+block.last().Len() != address || block.ends() {
+			if block != nil && !block.ends() && block.last().Address+block.last().Len() == address {
+				block.Successors = append(block.Successors, Edge{To: address, Kind: EdgeFallthrough})
+			}
+			block = &Block{Start: address}
+			graph.Blocks = append(graph.Blocks, block)
+		}
+		block.Instructions = append(block.Instructions, inst)
+		blockOf[address] = block
+	}
+	for _, s := range edges {
+		b := blockOf[s.from]
+		b.Successors = append(b.Successors, s.edge)
+	}
+	for address := range returns {
+		blockOf[address].Return = true
+	}
+	for address := range indirect {
+		blockOf[address].Indirect = true
+	}
+
+	for _, w := range writes {
+		if code[w.To] {
+			graph.SelfModifying = append(graph.SelfModifying, w)
+		}
+	}
+	sort.Slice(graph.SelfModifying, func(i, j int) bool {
+		return graph.SelfModifying[i].From < graph.SelfModifying[j].From
+	})
+	sort.Ints(graph.Invalid)
+	return graph
+}
+
+// last returns the last instruction in a block
+func (b *Block) last() Instruction {
+	return b.Instructions[len(b.Instructions)-1]
+}
+
+// ends returns whether the block ends in a jump or halt
+func (b *Block) ends() bool {
+	switch b.last().Opcode {
+	case OpcodeJumpIfTrue, OpcodeJumpIfFalse, OpcodeHalt:
+		return true
+	}
+	return false
+}
+
+// ToDOT returns the graph in the GraphViz DOT language, with a node for each basic block.
+// Instructions which are overwritten by the program are coloured red and marked with a '*'. Jumps to addresses which
+// do not hold a valid instruction lead to a red "invalid" node for the address.
+func (g *Graph) ToDOT() *dot.Graph {
+	modified := map[int]bool{}
+	for _, write := range g.SelfModifying {
+		modified[write.To] = true
+	}
+	declared := map[int]bool{}
+	for _, b := range g.Blocks {
+		declared[b.Start] = true
+	}
+
+	graph := dot.New("intcode", true)
+	for _, b := range g.Blocks {
+		lines := make([]string, len(b.Instructions))
+		colour := "black"
+		for i, inst := range b.Instructions {
+			lines[i] = inst.String()
+			for a := inst.Address; a < inst.Address+inst.Len(); a++ {
+				if modified[a] {
+					colour = "red"
+					lines[i] += " *"
+					break
+				}
+			}
+		}
+		if b.Return {
+			lines = append(lines, "return")
+		}
+		graph.Node(blockID(b.Start), dot.Attrs{
+			"label":    strings.Join(lines, `\l`) + `\l`,
+			"shape":    "box",
+			"fontname": "monospace",
+			"color":    colour,
+		})
+		for _, e := range b.Successors {
+			if !declared[e.To] {
+				graph.Node(blockID(e.To), dot.Attrs{
+					"label": fmt.Sprintf("%d: invalid", e.To),
+					"shape": "octagon",
+					"color": "red",
+				})
+			}
+			graph.Edge(blockID(b.Start), blockID(e.To), edgeStyles[e.Kind])
+		}
+	}
+	return graph
+}
+
+// blockID returns the DOT node ID of the basic block starting at an address
+func blockID(address int) string {
+	return fmt.Sprintf("b%d", address)
+}
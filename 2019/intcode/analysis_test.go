@@ -0,0 +1,121 @@
+package intcode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyse(t *testing.T) {
+	// Set the relative base, call a function which outputs 42 and returns, then halt
+	program := []int{
+		109, 100, // 0: arb #100
+		21101, 9, 0, 0, // 2: add #9, #0, rb[0]
+		1105, 1, 10, // 6: jt #1, #10
+		99,      // 9: halt
+		104, 42, // 10: out #42
+		2105, 1, 0, // 12: jt #1, rb[0]
+	}
+	graph := Analyse(program)
+
+	starts := []int{}
+	for _, b := range graph.Blocks {
+		starts = append(starts, b.Start)
+	}
+	if want := []int{0, 9, 10}; !reflect.DeepEqual(starts, want) {
+		t.Fatalf("Got blocks starting at %v, want %v", starts, want)
+	}
+	if want := []Edge{{To: 9, Kind: EdgeReturnSite}, {To: 10, Kind: EdgeCall}}; !reflect.DeepEqual(graph.Blocks[0].Successors, want) {
+		t.Errorf("Got successors %v, want %v", graph.Blocks[0].Successors, want)
+	}
+	if !graph.Blocks[2].Return {
+		t.Errorf("Block at 10 not recognised as a return")
+	}
+	if len(graph.SelfModifying) != 0 {
+		t.Errorf("Got self modifying writes %v, want none", graph.SelfModifying)
+	}
+
+	dot := graph.ToDOT().String()
+	for _, want := range []string{`"b0" -> "b10" [color="darkgreen", label="call", style="dashed"]`, `6: jt #1, #10`, `12: jt #1, rb[0]`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output %q does not contain %q", dot, want)
+		}
+	}
+}
+
+func TestAnalyseSelfModifying(t *testing.T) {
+	program := []int{1, 9, 10, 3, 2, 3, 11, 0, 99, 30, 40, 50}
+	graph := Analyse(program)
+	want := []Write{{From: 0, To: 3}, {From: 4, To: 0}}
+	if !reflect.DeepEqual(graph.SelfModifying, want) {
+		t.Errorf("Got %v, want %v", graph.SelfModifying, want)
+	}
+}
+
+func TestAnalyseBranches(t *testing.T) {
+	// Read a value and output 1 if it is non-zero, otherwise 0
+	program := []int{
+		3, 20, // 0: in [20]
+		1005, 20, 9, // 2: jt [20], #9
+		104, 0, // 5: out #0
+		99,     // 7: halt
+		0,      // 8: data
+		104, 1, // 9: out #1
+		1106, 0, 7, // 11: jf #0, #7
+	}
+	graph := Analyse(program)
+	got := map[int][]Edge{}
+	for _, b := range graph.Blocks {
+		got[b.Start] = b.Successors
+	}
+	want := map[int][]Edge{
+		0: {{To: 9, Kind: EdgeJump}, {To: 5, Kind: EdgeFallthrough}},
+		5: {{To: 7, Kind: EdgeFallthrough}},
+		7: nil,
+		9: {{To: 7, Kind: EdgeJump}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
+
+func TestToDOTMarksAndInvalidTargets(t *testing.T) {
+	program := []int{
+		1101, 1, 1, 5, // 0: add #1, #1, [5]
+		1101, 2, 2, 6, // 4: add #2, #2, [6]
+		1105, 1, 50, // 8: jt #1, #50
+	}
+	graph := Analyse(program)
+	if want := []int{50}; !reflect.DeepEqual(graph.Invalid, want) {
+		t.Fatalf("Got invalid addresses %v, want %v", graph.Invalid, want)
+	}
+
+	dot := graph.ToDOT().String()
+	if got := strings.Count(dot, " *"); got != 1 {
+		t.Errorf("Got %d self modification markers, want 1 in %q", got, dot)
+	}
+	for _, want := range []string{`"b0" -> "b50" [color="blue", style="solid"]`, `"b50" [color="red", label="50: invalid", shape="octagon"]`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output %q does not contain %q", dot, want)
+		}
+	}
+}
+
+func TestAnalyseSharedInvalidTarget(t *testing.T) {
+	program := []int{
+		1005, 10, 9, // 0: jt [10], #9
+		1105, 1, 9, // 3: jt #1, #9
+		99, 99, 99, // 6: halt
+		77, // 9: invalid
+		0,  // 10: data
+	}
+	graph := Analyse(program)
+	if want := []int{9}; !reflect.DeepEqual(graph.Invalid, want) {
+		t.Fatalf("Got invalid addresses %v, want %v", graph.Invalid, want)
+	}
+
+	dot := graph.ToDOT().String()
+	if got := strings.Count(dot, "\t\"b9\" ["); got != 1 {
+		t.Errorf("Got %d declarations of the invalid node, want 1 in %q", got, dot)
+	}
+}
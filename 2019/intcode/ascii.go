@@ -0,0 +1,35 @@
+package intcode
+
+import (
+	"strings"
+)
+
+// maxASCII is the largest value which is treated as an ASCII character in program output
+const maxASCII = 127
+
+// EncodeASCII converts lines of text in to Intcode input, terminating each line with a newline
+func EncodeASCII(lines ...string) []int {
+	input := []int{}
+	for _, line := range lines {
+		for _, r := range line {
+			input = append(input, int(r))
+		}
+		input = append(input, '\n')
+	}
+	return input
+}
+
+// DecodeASCII converts Intcode output in to text.
+// Values which are not ASCII characters, such as a final answer, are returned separately in the order they were output.
+func DecodeASCII(output []int) (string, []int) {
+	var builder strings.Builder
+	other := []int{}
+	for _, o := range output {
+		if o >= 0 && o <= maxASCII {
+			builder.WriteByte(byte(o))
+		} else {
+			other = append(other, o)
+		}
+	}
+	return builder.String(), other
+}
@@ -2,6 +2,7 @@
 package intcode
 
 import (
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
@@ -28,48 +29,56 @@ const (
 	ParameterModeRelative  = 2
 )
 
+// modeTable holds the parameter modes for every three digit mode prefix of an instruction.
+// Only the lowest three digits of the prefix are significant, so any instruction can be decoded with a single lookup.
+var modeTable [1000][3]int
+
+func init() {
+	for n := range modeTable {
+		modeTable[n] = [3]int{n % 10, n / 10 % 10, n / 100}
+	}
+}
+
 // Computer is an Intcode computer
 type Computer struct {
-	program                      []int
+	memory                       memory
 	instructionPtr, relativeBase int
 	chanIn                       <-chan int
 	chanOut                      chan<- int
 	blocking                     bool
 	defaultInput                 int
 	idleFor                      int
+	err                          error
+	// queue and outputs hold values for a computer which is stepped with Resume rather than run with channels
+	queue, outputs []int
+	halted         bool
 }
 
+// Status describes why a computer stopped executing when resumed
+type Status int
+
+// Statuses returned by Resume
+const (
+	// StatusNeedInput means the computer yielded on reading input with nothing queued
+	StatusNeedInput Status = iota
+	// StatusHalted means the program has halted
+	StatusHalted
+)
+
 // readOpcode returns the last two digits of an instruction which represent the opcode
 func readOpcode(number int) int {
 	return number % 100
 }
 
-// getDigits extracts the digits from a number
-func getDigits(n int, digits *[]int) {
-	if n < 10 {
-		*digits = append(*digits, n)
-	} else {
-		getDigits(n/10, digits)
-		*digits = append(*digits, n%10)
-	}
+// parameterModes returns the parameter modes from a number without allocating
+func parameterModes(number int) [3]int {
+	return modeTable[number%1000]
 }
 
 // readParameterMode returns a slice of the parameter modes from a number
 func readParameterMode(number int) []int {
-	digits := []int{}
-	getDigits(number, &digits)
-
-	// Reverse the digits so that the modes are in parameter order
-	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
-		digits[i], digits[j] = digits[j], digits[i]
-	}
-
-	// Copy to mode to ensure correct length
-	modeLength := 3
-	modes := make([]int, modeLength)
-	copy(modes, digits)
-
-	return modes
+	modes := parameterModes(number)
+	return modes[:]
 }
 
 // copyProgram returns a copy of the given program
@@ -82,7 +91,7 @@ func copyProgram(program []int) []int {
 // New creates a computer with the given program
 func New(program []int) *Computer {
 	return &Computer{
-		program:  copyProgram(program),
+		memory:   newMemory(program),
 		chanOut:  make(chan int),
 		blocking: true,
 	}
@@ -94,6 +103,11 @@ func (c *Computer) SetNonBlocking(input int) {
 	c.defaultInput = input
 }
 
+// SetMemoryLimit sets the maximum number of memory cells the program may use, zero means no limit
+func (c *Computer) SetMemoryLimit(n int) {
+	c.memory.limit = n
+}
+
 // setChanIn sets the input channel
 func (c *Computer) SetChanIn(ch <-chan int) {
 	c.chanIn = ch
@@ -104,209 +118,254 @@ func (c *Computer) SetChanOut(ch chan<- int) {
 	c.chanOut = ch
 }
 
-// getParameters returns the next n address locations from the instruction pointer
-func (c *Computer) getParameters(n int) []int {
-	params := make([]int, n)
-	for i := 0; i < n; i++ {
-		params[i] = c.program[c.instructionPtr+1+i]
+// fail records the first error encountered while running the program
+func (c *Computer) fail(err error) {
+	if c.err == nil {
+		c.err = err
 	}
-	return params
 }
 
-// checkAddress checks that an address is valid and if not grows the memory to accomodate it
-func (c *Computer) checkAddress(address int) {
-	if address >= len(c.program) {
-		newProgram := make([]int, address+1)
-		copy(newProgram, c.program)
-		c.program = newProgram
+// read returns the value held at an address
+func (c *Computer) read(address int) int {
+	value, err := c.memory.read(address)
+	if err != nil {
+		c.fail(err)
 	}
+	return value
 }
 
-// getParameterValueAddress returns the appropriate value and address for a parameter given the parameter mode
-func (c *Computer) getParameterValue(parameter, mode int) (int, int) {
-	var value, address int
+// write stores a value at an address
+func (c *Computer) write(address, value int) {
+	if err := c.memory.write(address, value); err != nil {
+		c.fail(err)
+	}
+}
 
+// address returns the memory address referred to by the nth parameter of the current instruction
+func (c *Computer) address(n, mode int) int {
+	parameter := c.read(c.instructionPtr + n)
 	switch mode {
-
 	case ParameterModePosition:
-		address = parameter
-		c.checkAddress(address)
-		value = c.program[address]
-
-	case ParameterModeImmediate:
-		address = -1
-		value = parameter
-
+		return parameter
 	case ParameterModeRelative:
-		address = parameter + c.relativeBase
-		c.checkAddress(address)
-		value = c.program[address]
-
+		return parameter + c.relativeBase
 	default:
-		log.Fatalf("Unknown parameter mode %d", mode)
+		c.fail(fmt.Errorf("unknown parameter mode %d at address %d", mode, c.instructionPtr))
+		return 0
 	}
-
-	return value, address
 }
 
-// add will execute the actions for OpcodeAdd
-func (c *Computer) add(modes []int) {
-	params := c.getParameters(3)
-	valueA, _ := c.getParameterValue(params[0], modes[0])
-	valueB, _ := c.getParameterValue(params[1], modes[1])
-	_, destAddress := c.getParameterValue(params[2], modes[2])
-	c.program[destAddress] = valueA + valueB
-	c.instructionPtr += 4
+// value returns the value of the nth parameter of the current instruction
+func (c *Computer) value(n, mode int) int {
+	if mode == ParameterModeImmediate {
+		return c.read(c.instructionPtr + n)
+	}
+	return c.read(c.address(n, mode))
 }
 
-// multiply will execute the actions for OpcodeMultiply
-func (c *Computer) multiply(modes []int) {
-	params := c.getParameters(3)
-	valueA, _ := c.getParameterValue(params[0], modes[0])
-	valueB, _ := c.getParameterValue(params[1], modes[1])
-	_, destAddress := c.getParameterValue(params[2], modes[2])
-	c.program[destAddress] = valueA * valueB
-	c.instructionPtr += 4
+// jump moves to the address given by the second parameter of a jump instruction if jump is true, or on to the next
+// instruction otherwise. Nothing changes if reading a parameter fails.
+func (c *Computer) jump(jump bool, mode int) {
+	if c.err != nil {
+		return
+	}
+	if !jump {
+		c.instructionPtr += 3
+		return
+	}
+	if target := c.value(2, mode); c.err == nil {
+		c.instructionPtr = target
+	}
 }
 
-// input will execute the actions for OpcodeInput
-func (c *Computer) input(modes []int) {
-	params := c.getParameters(1)
-	_, destAddress := c.getParameterValue(params[0], modes[0])
-	var in int
+// input returns the next input value, honouring the blocking setting
+func (c *Computer) input() int {
 	if c.blocking {
-		in = <-c.chanIn
-	} else {
-		select {
-		case val := <-c.chanIn:
-			in = val
-			c.idleFor = 0
-		default:
-			in = c.defaultInput
-			c.idleFor += 1
-		}
+		return <-c.chanIn
+	}
+	select {
+	case val := <-c.chanIn:
+		c.idleFor = 0
+		return val
+	default:
+		c.idleFor += 1
+		return c.defaultInput
 	}
-	c.program[destAddress] = in
-	c.instructionPtr += 2
 }
 
-// output will execute the actions for OpcodeOutput
-func (c *Computer) output(modes []int) {
-	params := c.getParameters(1)
-	value, _ := c.getParameterValue(params[0], modes[0])
-	c.chanOut <- value
-	c.instructionPtr += 2
+// boolToInt returns 1 for true and 0 for false
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
 }
 
-// jumpIfTrue will execute the actions for OpcodeJumpIfTrue
-func (c *Computer) jumpIfTrue(modes []int) {
-	params := c.getParameters(2)
-	valueA, _ := c.getParameterValue(params[0], modes[0])
-	if valueA != 0 {
-		valueB, _ := c.getParameterValue(params[1], modes[1])
-		c.instructionPtr = valueB
-	} else {
-		c.instructionPtr += 3
-	}
+// Run will run the Intcode computer until it halts or encounters an error.
+// The output channel is closed in either case.
+func (c *Computer) Run() error {
+	defer close(c.chanOut)
+	_, err := c.execute(false)
+	return err
 }
 
-// jumpIfFalse will execute the actions for OpcodeJumpIfFalse
-func (c *Computer) jumpIfFalse(modes []int) {
-	params := c.getParameters(2)
-	valueA, _ := c.getParameterValue(params[0], modes[0])
-	if valueA == 0 {
-		valueB, _ := c.getParameterValue(params[1], modes[1])
-		c.instructionPtr = valueB
-	} else {
-		c.instructionPtr += 3
-	}
+// Input queues values to be read by a computer which is stepped with Resume
+func (c *Computer) Input(values ...int) {
+	c.queue = append(c.queue, values...)
 }
 
-// lessThan will execute the actions for OpcodeLessThan
-func (c *Computer) lessThan(modes []int) {
-	params := c.getParameters(3)
-	valueA, _ := c.getParameterValue(params[0], modes[0])
-	valueB, _ := c.getParameterValue(params[1], modes[1])
-	_, destAddress := c.getParameterValue(params[2], modes[2])
-	if valueA < valueB {
-		c.program[destAddress] = 1
-	} else {
-		c.program[destAddress] = 0
-	}
-	c.instructionPtr += 4
+// Queued returns the number of input values waiting to be read
+func (c *Computer) Queued() int {
+	return len(c.queue)
 }
 
-// equals will execute the actions for OpcodeEquals
-func (c *Computer) equals(modes []int) {
-	params := c.getParameters(3)
-	valueA, _ := c.getParameterValue(params[0], modes[0])
-	valueB, _ := c.getParameterValue(params[1], modes[1])
-	_, destAddress := c.getParameterValue(params[2], modes[2])
-	if valueA == valueB {
-		c.program[destAddress] = 1
-	} else {
-		c.program[destAddress] = 0
-	}
-	c.instructionPtr += 4
+// Outputs returns and clears the values output since it was last called
+func (c *Computer) Outputs() []int {
+	outputs := c.outputs
+	c.outputs = nil
+	return outputs
 }
 
-// relativeBaseOffset will execute the actions for OpcodeRelativeBaseOffset:
-func (c *Computer) relativeBaseOffset(modes []int) {
-	params := c.getParameters(1)
-	value, _ := c.getParameterValue(params[0], modes[0])
-	c.relativeBase += value
-	c.instructionPtr += 2
+// Resume runs the computer on the current goroutine using queued input until it needs input which has not been queued or halts.
+// A blocking computer stops before the input instruction so it is retried on the next call,
+// while a non-blocking computer reads its default input and yields after it.
+func (c *Computer) Resume() (Status, error) {
+	if c.halted {
+		return StatusHalted, nil
+	}
+	return c.execute(true)
 }
 
-// Run will run the Intcode computer until it halts
-func (c *Computer) Run() {
-	for {
-		opcode := readOpcode(c.program[c.instructionPtr])
-		modes := readParameterMode(c.program[c.instructionPtr] / 100)
-		switch opcode {
+// execute runs the fetch, decode and execute loop.
+// When stepped the queued input and output slices are used instead of channels.
+func (c *Computer) execute(stepped bool) (Status, error) {
+	for c.err == nil {
+		instruction := c.read(c.instructionPtr)
+		if c.err != nil {
+			return StatusHalted, c.err
+		}
+		if instruction < 0 {
+			return StatusHalted, fmt.Errorf("unknown opcode %d at address %d", readOpcode(instruction), c.instructionPtr)
+		}
+		modes := parameterModes(instruction / 100)
+
+		switch opcode := readOpcode(instruction); opcode {
 
 		case OpcodeAdd:
-			c.add(modes)
+			a, b, dest := c.value(1, modes[0]), c.value(2, modes[1]), c.address(3, modes[2])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.write(dest, a+b)
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.instructionPtr += 4
 
 		case OpcodeMultiply:
-			c.multiply(modes)
+			a, b, dest := c.value(1, modes[0]), c.value(2, modes[1]), c.address(3, modes[2])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.write(dest, a*b)
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.instructionPtr += 4
 
 		case OpcodeInput:
-			c.input(modes)
+			dest := c.address(1, modes[0])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			// Check the write before taking an input so a fault leaves the input to be read again
+			if err := c.memory.writable(dest); err != nil {
+				c.fail(err)
+				return StatusHalted, c.err
+			}
+			if !stepped {
+				c.write(dest, c.input())
+				c.instructionPtr += 2
+				break
+			}
+			if len(c.queue) > 0 {
+				c.write(dest, c.queue[0])
+				c.queue = c.queue[1:]
+				c.idleFor = 0
+				c.instructionPtr += 2
+				break
+			}
+			c.idleFor += 1
+			if !c.blocking {
+				c.write(dest, c.defaultInput)
+				c.instructionPtr += 2
+			}
+			return StatusNeedInput, c.err
 
 		case OpcodeOutput:
-			c.output(modes)
+			value := c.value(1, modes[0])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			if stepped {
+				c.outputs = append(c.outputs, value)
+			} else {
+				c.chanOut <- value
+			}
+			c.instructionPtr += 2
 
 		case OpcodeJumpIfTrue:
-			c.jumpIfTrue(modes)
+			c.jump(c.value(1, modes[0]) != 0, modes[1])
 
 		case OpcodeJumpIfFalse:
-			c.jumpIfFalse(modes)
+			c.jump(c.value(1, modes[0]) == 0, modes[1])
 
 		case OpcodeLessThan:
-			c.lessThan(modes)
+			a, b, dest := c.value(1, modes[0]), c.value(2, modes[1]), c.address(3, modes[2])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.write(dest, boolToInt(a < b))
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.instructionPtr += 4
 
 		case OpcodeEquals:
-			c.equals(modes)
+			a, b, dest := c.value(1, modes[0]), c.value(2, modes[1]), c.address(3, modes[2])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.write(dest, boolToInt(a == b))
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.instructionPtr += 4
 
 		case OpcodeRelativeBaseOffset:
-			c.relativeBaseOffset(modes)
+			offset := c.value(1, modes[0])
+			if c.err != nil {
+				return StatusHalted, c.err
+			}
+			c.relativeBase += offset
+			c.instructionPtr += 2
 
 		case OpcodeHalt:
-			close(c.chanOut)
-			return
+			c.halted = true
+			return StatusHalted, nil
 
 		default:
-			log.Fatalf("Unknown opcode %d", opcode)
+			return StatusHalted, fmt.Errorf("unknown opcode %d at address %d", opcode, c.instructionPtr)
 
 		}
 	}
-
+	return StatusHalted, c.err
 }
 
-// Program returns the current state of the Incode program
+// Program returns the current state of the Incode program.
+// Only the contiguous low memory is returned, values written to far addresses are not included.
 func (c *Computer) Program() []int {
-	return c.program
+	return c.memory.dense
 }
 
 // IdleFor returns whether the computer has not received any input for n read attempts
@@ -1,6 +1,9 @@
 package intcode
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
@@ -94,3 +97,240 @@ func TestReadParameterMode(t *testing.T) {
 		})
 	}
 }
+
+func TestParameterModes(t *testing.T) {
+	tests := []struct {
+		input int
+		want  [3]int
+		name  string
+	}{
+		{input: 123, want: [3]int{3, 2, 1}, name: "three digits"},
+		{input: 42, want: [3]int{2, 4, 0}, name: "two digits"},
+		{input: 21105 / 100, want: [3]int{1, 1, 2}, name: "instruction prefix"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parameterModes(tc.input)
+			if got != tc.want {
+				t.Errorf("Got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// tractorBeam is a small Intcode program which reads an x and y coordinate and outputs whether the point
+// lies within a beam, mimicking the shape of the 2019 day 19 tractor beam program
+var tractorBeam = []int{
+	3, 100, 3, 101, // read x and y
+	1002, 101, 3, 102, // y*3
+	1002, 100, 2, 103, // x*2
+	7, 102, 103, 104, // y*3 < x*2
+	1002, 100, 5, 105, // x*5
+	7, 105, 102, 106, // x*5 < y*3
+	1, 104, 106, 107, // sum of out of beam checks
+	1008, 107, 0, 107, // in beam if no checks failed
+	4, 107,
+	99,
+}
+
+// boostQuine is the 2019 day 9 example program which outputs a copy of itself using relative mode
+var boostQuine = []int{109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99}
+
+// benchmarkProgram returns the program read from the file named by the environment variable, skipping the benchmark
+// if it is unset. This allows benchmarking against real puzzle inputs which are not committed to the repository.
+func benchmarkProgram(b *testing.B, env string) []int {
+	path := os.Getenv(env)
+	if path == "" {
+		b.Skipf("set %s to the puzzle input to run this benchmark", env)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return ReadProgram(data)
+}
+
+// runWithInputs runs a program to completion with the given inputs and returns the number of outputs
+func runWithInputs(program []int, inputs ...int) int {
+	chanIn := make(chan int, len(inputs))
+	chanOut := make(chan int, 64)
+	for _, in := range inputs {
+		chanIn <- in
+	}
+	computer := New(program)
+	computer.SetChanIn(chanIn)
+	computer.SetChanOut(chanOut)
+	go computer.Run()
+	n := 0
+	for range chanOut {
+		n++
+	}
+	return n
+}
+
+// BenchmarkBoost runs the 2019 day 9 BOOST program in sensor boost mode, read from the file named by INTCODE_BOOST_INPUT
+func BenchmarkBoost(b *testing.B) {
+	program := benchmarkProgram(b, "INTCODE_BOOST_INPUT")
+	for i := 0; i < b.N; i++ {
+		runWithInputs(program, 2)
+	}
+}
+
+// BenchmarkTractorBeamScan scans a 50x50 area with the 2019 day 19 tractor beam program, read from the file named by
+// INTCODE_TRACTOR_BEAM_INPUT
+func BenchmarkTractorBeamScan(b *testing.B) {
+	program := benchmarkProgram(b, "INTCODE_TRACTOR_BEAM_INPUT")
+	for i := 0; i < b.N; i++ {
+		for y := 0; y < 50; y++ {
+			for x := 0; x < 50; x++ {
+				runWithInputs(program, x, y)
+			}
+		}
+	}
+}
+
+func TestTractorBeam(t *testing.T) {
+	tests := []struct {
+		x, y, want int
+	}{
+		{x: 0, y: 0, want: 1},
+		{x: 10, y: 10, want: 1},
+		{x: 10, y: 2, want: 0},
+		{x: 2, y: 10, want: 0},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d,%d", tc.x, tc.y), func(t *testing.T) {
+			chanIn := make(chan int, 2)
+			chanOut := make(chan int, 1)
+			chanIn <- tc.x
+			chanIn <- tc.y
+			computer := New(tractorBeam)
+			computer.SetChanIn(chanIn)
+			computer.SetChanOut(chanOut)
+			go computer.Run()
+			if got := <-chanOut; got != tc.want {
+				t.Errorf("Got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBoostQuine(t *testing.T) {
+	chanOut := make(chan int, len(boostQuine))
+	computer := New(boostQuine)
+	computer.SetChanOut(chanOut)
+	computer.Run()
+	got := []int{}
+	for v := range chanOut {
+		got = append(got, v)
+	}
+	if !intSliceEqual(got, boostQuine) {
+		t.Errorf("Got %v, want %v", got, boostQuine)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []int
+		limit   int
+		wantErr error
+	}{
+		{name: "far address", program: []int{1101, 1, 2, 1000000000000, 4, 1000000000000, 99}, wantErr: nil},
+		{name: "negative position", program: []int{1, -1, 0, 0, 99}, wantErr: ErrNegativeAddress},
+		{name: "negative relative", program: []int{109, -10, 21101, 1, 2, 0, 99}, wantErr: ErrNegativeAddress},
+		{name: "negative jump", program: []int{1105, 1, -5, 99}, wantErr: ErrNegativeAddress},
+		{name: "within limit", program: []int{1101, 1, 2, 9, 99}, limit: 10, wantErr: nil},
+		{name: "dense limit", program: []int{1101, 1, 2, 10, 99}, limit: 10, wantErr: ErrMemoryLimit},
+		{name: "sparse limit", program: []int{1101, 1, 2, 1000000000000, 99}, limit: 5, wantErr: ErrMemoryLimit},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chanOut := make(chan int, 1)
+			computer := New(tc.program)
+			computer.SetChanOut(chanOut)
+			computer.SetMemoryLimit(tc.limit)
+			if err := computer.Run(); !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFaultHasNoSideEffects(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []int
+		inputs  []int
+	}{
+		{name: "add", program: []int{1, -1, 0, 0, 99}},
+		{name: "multiply", program: []int{2, 0, -1, 0, 99}},
+		{name: "less than", program: []int{7, -1, 0, 0, 99}},
+		{name: "equals", program: []int{8, 0, -1, 0, 99}},
+		{name: "jump", program: []int{105, 1, -1, 99}},
+		{name: "relative base", program: []int{9, -1, 99}},
+		{name: "input", program: []int{3, -1, 99}, inputs: []int{5}},
+		{name: "input limit", program: []int{3, 1000000000000, 99}, inputs: []int{5}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			want := copyProgram(tc.program)
+			computer := New(tc.program)
+			computer.SetMemoryLimit(len(tc.program))
+			computer.Input(tc.inputs...)
+			if _, err := computer.Resume(); !errors.Is(err, ErrNegativeAddress) && !errors.Is(err, ErrMemoryLimit) {
+				t.Fatalf("Got error %v, want %v or %v", err, ErrNegativeAddress, ErrMemoryLimit)
+			}
+			if got := computer.Queued(); got != len(tc.inputs) {
+				t.Errorf("Got %d queued inputs after the fault, want %d", got, len(tc.inputs))
+			}
+			if !intSliceEqual(computer.Program(), want) {
+				t.Errorf("Got memory %v after the fault, want %v", computer.Program(), want)
+			}
+			if computer.instructionPtr != 0 || computer.relativeBase != 0 {
+				t.Errorf("Got instruction pointer %d and relative base %d after the fault, want 0",
+					computer.instructionPtr, computer.relativeBase,
+				)
+			}
+		})
+	}
+}
+
+func TestInputFaultLeavesInput(t *testing.T) {
+	chanIn := make(chan int, 1)
+	chanIn <- 5
+	computer := New([]int{3, -1, 99})
+	computer.SetChanIn(chanIn)
+	if err := computer.Run(); !errors.Is(err, ErrNegativeAddress) {
+		t.Fatalf("Got error %v, want %v", err, ErrNegativeAddress)
+	}
+	if got := len(chanIn); got != 1 {
+		t.Errorf("Got %d values left on the input channel, want 1", got)
+	}
+
+	computer = New([]int{3, -1, 99})
+	computer.SetNonBlocking(-1)
+	if _, err := computer.Resume(); !errors.Is(err, ErrNegativeAddress) {
+		t.Fatalf("Got error %v, want %v", err, ErrNegativeAddress)
+	}
+	if computer.instructionPtr != 0 || computer.idleFor != 0 {
+		t.Errorf("Got instruction pointer %d and idle count %d after the fault, want 0",
+			computer.instructionPtr, computer.idleFor,
+		)
+	}
+}
+
+func TestFarAddressOutput(t *testing.T) {
+	chanOut := make(chan int, 1)
+	computer := New([]int{1101, 1, 2, 1000000000000, 4, 1000000000000, 99})
+	computer.SetChanOut(chanOut)
+	if err := computer.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-chanOut; got != 3 {
+		t.Errorf("Got %d, want 3", got)
+	}
+	if got := len(computer.Program()); got != 7 {
+		t.Errorf("Got dense memory of length %d, want 7", got)
+	}
+}
@@ -0,0 +1,92 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// denseLimit is the number of low addresses held in contiguous memory, addresses beyond this are held sparsely
+const denseLimit = 1 << 20
+
+var (
+	// ErrNegativeAddress is returned when a program accesses memory below address zero
+	ErrNegativeAddress = errors.New("negative memory address")
+	// ErrMemoryLimit is returned when a write would take the program beyond its memory limit
+	ErrMemoryLimit = errors.New("memory limit exceeded")
+)
+
+// memory is the memory of an Intcode computer.
+// Low addresses are held in a slice which grows as needed while far addresses are held in a map,
+// so a program writing to a huge address only costs a single map entry.
+type memory struct {
+	dense  []int
+	sparse map[int]int
+	// limit is the maximum number of memory cells which may be in use, zero means no limit
+	limit int
+}
+
+// newMemory returns memory initialised with a copy of the program
+func newMemory(program []int) memory {
+	return memory{dense: copyProgram(program)}
+}
+
+// size returns the number of memory cells in use
+func (m *memory) size() int {
+	return len(m.dense) + len(m.sparse)
+}
+
+// read returns the value held at an address, unwritten addresses hold zero
+func (m *memory) read(address int) (int, error) {
+	if address >= 0 && address < len(m.dense) {
+		return m.dense[address], nil
+	}
+	if address < 0 {
+		return 0, fmt.Errorf("reading address %d: %w", address, ErrNegativeAddress)
+	}
+	return m.sparse[address], nil
+}
+
+// write stores a value at an address, growing the memory if needed
+func (m *memory) write(address, value int) error {
+	if err := m.writable(address); err != nil {
+		return err
+	}
+	if address < len(m.dense) {
+		m.dense[address] = value
+		return nil
+	}
+	if address < denseLimit {
+		// Growth is delegated to append so that repeated writes just past the end are amortised
+		m.dense = append(m.dense, make([]int, address+1-len(m.dense))...)
+		m.dense[address] = value
+		return nil
+	}
+	if m.sparse == nil {
+		m.sparse = map[int]int{}
+	}
+	m.sparse[address] = value
+	return nil
+}
+
+// writable returns the error a write to an address would fail with, without changing memory
+func (m *memory) writable(address int) error {
+	if address >= 0 && address < len(m.dense) {
+		return nil
+	}
+	if address < 0 {
+		return fmt.Errorf("writing address %d: %w", address, ErrNegativeAddress)
+	}
+
+	if address < denseLimit {
+		grow := address + 1 - len(m.dense)
+		if m.limit > 0 && m.size()+grow > m.limit {
+			return fmt.Errorf("writing address %d: %w", address, ErrMemoryLimit)
+		}
+		return nil
+	}
+
+	if _, ok := m.sparse[address]; !ok && m.limit > 0 && m.size()+1 > m.limit {
+		return fmt.Errorf("writing address %d: %w", address, ErrMemoryLimit)
+	}
+	return nil
+}
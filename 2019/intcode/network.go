@@ -0,0 +1,172 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDeadlock is returned when a network is idle and has no idle policy to wake it
+var ErrDeadlock = errors.New("network deadlocked")
+
+// defaultIdleReads is the number of consecutive empty reads after which a computer is considered idle
+const defaultIdleReads = 2
+
+// Packet is a message sent between computers on a network
+type Packet struct {
+	Source, Destination int
+	Data                []int
+}
+
+// Router converts the output of a computer into packets
+type Router interface {
+	// Route returns the packets formed from the start of a computer's pending output and the number of values used
+	Route(source int, output []int) ([]Packet, int)
+}
+
+// AddressedRouter forms packets from triples of a destination address followed by X and Y values
+type AddressedRouter struct{}
+
+// Route implements Router
+func (AddressedRouter) Route(source int, output []int) ([]Packet, int) {
+	packets := []Packet{}
+	used := 0
+	for ; used+3 <= len(output); used += 3 {
+		packets = append(packets, Packet{
+			Source:      source,
+			Destination: output[used],
+			Data:        []int{output[used+1], output[used+2]},
+		})
+	}
+	return packets, used
+}
+
+// RingRouter sends every output value to the next computer, with the last computer sending to the first
+type RingRouter struct {
+	Size int
+}
+
+// Route implements Router
+func (r RingRouter) Route(source int, output []int) ([]Packet, int) {
+	packets := make([]Packet, len(output))
+	for i, value := range output {
+		packets[i] = Packet{Source: source, Destination: (source + 1) % r.Size, Data: []int{value}}
+	}
+	return packets, len(output)
+}
+
+// Policy decides what happens to packets sent outside of a network and when a network is idle
+type Policy interface {
+	// Deliver receives a packet addressed outside of the network and returns true to stop the network
+	Deliver(p Packet) bool
+	// Idle is called when every running computer is waiting for input and returns packets to inject and true to stop the network
+	Idle() ([]Packet, bool)
+}
+
+// Network schedules a number of computers round-robin on a single goroutine and routes packets between them.
+// Given the same programs and inputs the order of execution and the packet log are always the same.
+type Network struct {
+	computers []*Computer
+	pending   [][]int
+	router    Router
+	policy    Policy
+	idleReads int
+	log       []Packet
+}
+
+// NewNetwork creates a network of computers which have their output formed into packets by the router.
+// The policy may be nil if packets never leave the network and it is never expected to become idle.
+func NewNetwork(computers []*Computer, router Router, policy Policy) *Network {
+	return &Network{
+		computers: computers,
+		pending:   make([][]int, len(computers)),
+		router:    router,
+		policy:    policy,
+		idleReads: defaultIdleReads,
+	}
+}
+
+// SetIdleReads sets how many consecutive reads with no input a computer must make to be considered idle
+func (n *Network) SetIdleReads(reads int) {
+	n.idleReads = reads
+}
+
+// Log returns every packet sent so far in the order they were sent
+func (n *Network) Log() []Packet {
+	return n.log
+}
+
+// send routes a packet to its destination computer or the policy, returning true if the network should stop
+func (n *Network) send(p Packet) (bool, error) {
+	n.log = append(n.log, p)
+	if p.Destination >= 0 && p.Destination < len(n.computers) {
+		n.computers[p.Destination].Input(p.Data...)
+		return false, nil
+	}
+	if n.policy == nil {
+		return false, fmt.Errorf("packet %v sent to address %d outside of network", p.Data, p.Destination)
+	}
+	return n.policy.Deliver(p), nil
+}
+
+// idle returns whether every running computer is waiting for input with nothing queued
+func (n *Network) idle() bool {
+	for _, c := range n.computers {
+		if c.halted {
+			continue
+		}
+		if c.Queued() > 0 || !c.IdleFor(n.idleReads) {
+			return false
+		}
+	}
+	return true
+}
+
+// Run runs the network until every computer halts or the policy stops it
+func (n *Network) Run() error {
+	for {
+		sent := false
+		running := false
+		for i, c := range n.computers {
+			status, err := c.Resume()
+			if err != nil {
+				return fmt.Errorf("computer %d: %w", i, err)
+			}
+			if status != StatusHalted {
+				running = true
+			}
+
+			n.pending[i] = append(n.pending[i], c.Outputs()...)
+			packets, used := n.router.Route(i, n.pending[i])
+			n.pending[i] = n.pending[i][used:]
+			for _, p := range packets {
+				sent = true
+				if stop, err := n.send(p); err != nil || stop {
+					return err
+				}
+			}
+		}
+
+		if !running {
+			return nil
+		}
+		if sent || !n.idle() {
+			continue
+		}
+
+		if n.policy == nil {
+			return ErrDeadlock
+		}
+		packets, stop := n.policy.Idle()
+		if stop {
+			return nil
+		}
+		if len(packets) == 0 {
+			return ErrDeadlock
+		}
+		for _, p := range packets {
+			if stop, err := n.send(p); err != nil || stop {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package intcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingNetwork(t *testing.T) {
+	program := []int{3, 26, 1001, 26, -4, 26, 3, 27, 1002, 27, 2, 27, 1, 27, 26, 27, 4, 27, 1001, 28, -1, 28, 1005, 28, 6, 99, 0, 0, 5}
+	phases := []int{9, 8, 7, 6, 5}
+	want := 139629729
+
+	computers := make([]*Computer, len(phases))
+	for i, phase := range phases {
+		computers[i] = New(program)
+		computers[i].Input(phase)
+	}
+	computers[0].Input(0)
+
+	network := NewNetwork(computers, RingRouter{Size: len(computers)}, nil)
+	if err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+	log := network.Log()
+	if got := log[len(log)-1].Data[0]; got != want {
+		t.Errorf("Got %d, want %d", got, want)
+	}
+}
+
+func TestRingNetworkDeadlock(t *testing.T) {
+	// Both computers wait for input which never arrives
+	computers := []*Computer{New([]int{3, 0, 99}), New([]int{3, 0, 99})}
+	network := NewNetwork(computers, RingRouter{Size: len(computers)}, nil)
+	if err := network.Run(); err != ErrDeadlock {
+		t.Errorf("Got error %v, want %v", err, ErrDeadlock)
+	}
+}
+
+// collector is a policy which collects packets sent outside the network and stops when the network is idle
+type collector struct {
+	packets []Packet
+	idle    int
+}
+
+func (c *collector) Deliver(p Packet) bool {
+	c.packets = append(c.packets, p)
+	return false
+}
+
+func (c *collector) Idle() ([]Packet, bool) {
+	c.idle++
+	return nil, true
+}
+
+func TestAddressedNetwork(t *testing.T) {
+	// Read the network address, send a packet of the address and double the address to 255 then poll for input forever
+	program := []int{3, 50, 104, 255, 4, 50, 1002, 50, 2, 51, 4, 51, 3, 52, 1105, 1, 12}
+	computers := make([]*Computer, 3)
+	for i := range computers {
+		computers[i] = New(program)
+		computers[i].SetNonBlocking(-1)
+		computers[i].Input(i)
+	}
+
+	policy := &collector{}
+	network := NewNetwork(computers, AddressedRouter{}, policy)
+	if err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Packet{
+		{Source: 0, Destination: 255, Data: []int{0, 0}},
+		{Source: 1, Destination: 255, Data: []int{1, 2}},
+		{Source: 2, Destination: 255, Data: []int{2, 4}},
+	}
+	if !reflect.DeepEqual(policy.packets, want) {
+		t.Errorf("Got packets %v, want %v", policy.packets, want)
+	}
+	if !reflect.DeepEqual(network.Log(), want) {
+		t.Errorf("Got log %v, want %v", network.Log(), want)
+	}
+	if policy.idle != 1 {
+		t.Errorf("Got %d idle calls, want 1", policy.idle)
+	}
+}
@@ -0,0 +1,201 @@
+package intcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RunASCII runs an ASCII program interactively.
+// Program output is written to out and each time the program needs input a line is read from in.
+// Every line sent to the program is also written to transcript if it is not nil, so that a transcript
+// can be replayed later by prepending it to in.
+func RunASCII(program []int, in io.Reader, out, transcript io.Writer) error {
+	computer := New(program)
+	scanner := bufio.NewScanner(in)
+	for {
+		status, err := computer.Resume()
+		if err != nil {
+			return err
+		}
+
+		text, other := DecodeASCII(computer.Outputs())
+		fmt.Fprint(out, text)
+		for _, o := range other {
+			fmt.Fprintln(out, o)
+		}
+
+		if status == StatusHalted {
+			return nil
+		}
+
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		line := scanner.Text()
+		if transcript != nil {
+			fmt.Fprintln(transcript, line)
+		}
+		computer.Input(EncodeASCII(line)...)
+	}
+}
+
+// Arcade tile IDs
+const (
+	tileEmpty  = 0
+	tileWall   = 1
+	tileBlock  = 2
+	tilePaddle = 3
+	tileBall   = 4
+)
+
+// arcadeTiles maps tile IDs to the characters used to draw them
+var arcadeTiles = map[int]byte{
+	tileEmpty:  ' ',
+	tileWall:   '#',
+	tileBlock:  '=',
+	tilePaddle: '-',
+	tileBall:   'o',
+}
+
+// Joystick keys, the arrow keys may also be used for left and right
+const (
+	keyLeft    = 'a'
+	keyNeutral = 's'
+	keyRight   = 'd'
+)
+
+// arcade is the state of the arcade screen
+type arcade struct {
+	tiles      map[[2]int]int
+	score      int
+	maxX, maxY int
+}
+
+// update applies x, y, tile ID triples output by the program
+func (a *arcade) update(output []int) {
+	for i := 0; i+3 <= len(output); i += 3 {
+		x, y, id := output[i], output[i+1], output[i+2]
+		if x == -1 && y == 0 {
+			a.score = id
+			continue
+		}
+		a.tiles[[2]int{x, y}] = id
+		a.maxX = max(a.maxX, x)
+		a.maxY = max(a.maxY, y)
+	}
+}
+
+// render returns the screen as text
+func (a *arcade) render() string {
+	var builder strings.Builder
+	for y := 0; y <= a.maxY; y++ {
+		for x := 0; x <= a.maxX; x++ {
+			builder.WriteByte(arcadeTiles[a.tiles[[2]int{x, y}]])
+		}
+		builder.WriteByte('\n')
+	}
+	fmt.Fprintf(&builder, "Score: %d\n", a.score)
+	return builder.String()
+}
+
+// readJoystick reads keys until one moves the joystick and returns the position and key.
+// Newlines are ignored so that recorded transcripts may be split over lines.
+func readJoystick(r *bufio.Reader) (int, byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		switch b {
+		case '\n', '\r':
+			continue
+		case keyLeft:
+			return -1, keyLeft, nil
+		case keyRight:
+			return 1, keyRight, nil
+		case '\x1b':
+			// Arrow keys are sent as the escape sequences ESC [ C and ESC [ D
+			seq := make([]byte, 2)
+			if _, err := io.ReadFull(r, seq); err != nil {
+				return 0, 0, err
+			}
+			switch {
+			case seq[0] == '[' && seq[1] == 'D':
+				return -1, keyLeft, nil
+			case seq[0] == '[' && seq[1] == 'C':
+				return 1, keyRight, nil
+			}
+		}
+		return 0, keyNeutral, nil
+	}
+}
+
+// RunArcade runs the arcade cabinet program interactively in free play, which is set by writing 2 to address 0 of a
+// copy of the program. Without it the cabinet draws the screen once and halts without reading the joystick.
+// The screen is drawn to out from the tile triples output by the program and the joystick is read from the keys in in.
+// Each joystick move is written to transcript as a key if it is not nil, so that a game can be replayed later.
+func RunArcade(program []int, in io.Reader, out, transcript io.Writer) error {
+	program = copyProgram(program)
+	program[0] = 2
+	computer := New(program)
+	screen := arcade{tiles: map[[2]int]int{}}
+	keys := bufio.NewReader(in)
+	pending := []int{}
+	for {
+		status, err := computer.Resume()
+		if err != nil {
+			return err
+		}
+
+		pending = append(pending, computer.Outputs()...)
+		used := len(pending) - len(pending)%3
+		screen.update(pending[:used])
+		pending = pending[used:]
+
+		// Clear the terminal before drawing each frame
+		fmt.Fprint(out, "\x1b[H\x1b[2J", screen.render())
+
+		if status == StatusHalted {
+			return nil
+		}
+
+		position, key, err := readJoystick(keys)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if transcript != nil {
+			fmt.Fprintf(transcript, "%c", key)
+		}
+		computer.Input(position)
+	}
+}
+
+// RawMode puts the terminal attached to standard input in to unbuffered mode without echo, so that single key presses
+// can be read. The returned function restores the terminal.
+// Standard input which is redirected from a file or pipe is left alone, as keys are then read without a terminal.
+func RawMode() (func(), error) {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return func() {}, nil
+	}
+	stty := func(args ...string) error {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		return cmd.Run()
+	}
+	if err := stty("cbreak", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty("-cbreak", "echo") }, nil
+}
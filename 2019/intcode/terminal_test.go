@@ -0,0 +1,105 @@
+package intcode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeASCII(t *testing.T) {
+	got := EncodeASCII("NOT A J", "WALK")
+	want := []int{'N', 'O', 'T', ' ', 'A', ' ', 'J', '\n', 'W', 'A', 'L', 'K', '\n'}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
+
+func TestDecodeASCII(t *testing.T) {
+	text, other := DecodeASCII([]int{'O', 'K', '\n', 19352638})
+	if text != "OK\n" {
+		t.Errorf("Got text %q, want %q", text, "OK\n")
+	}
+	if !reflect.DeepEqual(other, []int{19352638}) {
+		t.Errorf("Got other %v, want %v", other, []int{19352638})
+	}
+}
+
+func TestRunASCII(t *testing.T) {
+	// Echo characters until a newline is read
+	program := []int{3, 20, 4, 20, 1008, 20, 10, 21, 1006, 21, 0, 99}
+	var out, transcript strings.Builder
+	if err := RunASCII(program, strings.NewReader("hi\n"), &out, &transcript); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "hi\n" {
+		t.Errorf("Got output %q, want %q", got, "hi\n")
+	}
+	if got := transcript.String(); got != "hi\n" {
+		t.Errorf("Got transcript %q, want %q", got, "hi\n")
+	}
+}
+
+func TestRunArcade(t *testing.T) {
+	// Draw a paddle and ball then output the joystick position as the score.
+	// The first instruction is harmless whether address 0 holds 1 or is set to 2 for free play.
+	program := []int{
+		1, 60, 60, 60,
+		104, 0, 104, 0, 104, 3,
+		104, 1, 104, 0, 104, 4,
+		3, 50,
+		104, -1, 104, 0, 4, 50,
+		99,
+	}
+	tests := []struct {
+		name, keys, want string
+	}{
+		{name: "key", keys: "d", want: "-o\nScore: 1\n"},
+		{name: "arrow", keys: "\n\x1b[D", want: "-o\nScore: -1\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out, transcript strings.Builder
+			if err := RunArcade(program, strings.NewReader(tc.keys), &out, &transcript); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); !strings.HasSuffix(got, tc.want) {
+				t.Errorf("Got screen %q, want suffix %q", got, tc.want)
+			}
+			replay := strings.NewReader(transcript.String())
+			var replayed strings.Builder
+			if err := RunArcade(program, replay, &replayed, nil); err != nil {
+				t.Fatal(err)
+			}
+			if replayed.String() != out.String() {
+				t.Errorf("Replayed screen %q, want %q", replayed.String(), out.String())
+			}
+		})
+	}
+}
+
+func TestRunArcadeFreePlay(t *testing.T) {
+	// Like the cabinet, halt at once unless address 0 has been set to 2 for free play.
+	// The instruction at 0 adds or multiplies 1 and 3, and only the product jumps on to read the joystick.
+	program := make([]int, 34)
+	copy(program, []int{
+		1, 30, 31, 32,
+		1008, 32, 3, 32,
+		1005, 32, 12,
+		99,
+		3, 33,
+		104, -1, 104, 0, 4, 33,
+		99,
+	})
+	program[30], program[31] = 1, 3
+
+	var out strings.Builder
+	if err := RunArcade(program, strings.NewReader("d"), &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "Score: 1\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("Got screen %q, want suffix %q as the joystick must be read", got, want)
+	}
+	if program[0] != 1 {
+		t.Errorf("RunArcade changed address 0 of the caller's program to %d", program[0])
+	}
+}
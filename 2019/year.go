@@ -2,7 +2,9 @@
 package aoc2019
 
 import (
+	"io"
 	"log"
+	"os"
 
 //	"github.com/maze-mapper/advent-of-code/2019/01"
 	"github.com/maze-mapper/advent-of-code/2019/02"
@@ -29,6 +31,8 @@ import (
 	"github.com/maze-mapper/advent-of-code/2019/23"
 //	"github.com/maze-mapper/advent-of-code/2019/24"
 //	"github.com/maze-mapper/advent-of-code/2019/25"
+	"github.com/maze-mapper/advent-of-code/2019/intcode"
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 func Run(day, inputFile string) {
@@ -87,5 +91,68 @@ func Run(day, inputFile string) {
 	default:
 		log.Fatal(day, " is not a valid day")
 	}
+	if dot.Enabled() {
+		exportGraph(inputFile)
+	}
 	f(inputFile)
 }
+
+// exportGraph exports the control flow graph of the Intcode program in inputFile, showing the instructions which the
+// program overwrites
+func exportGraph(inputFile string) {
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graph := intcode.Analyse(intcode.ReadProgram(data))
+	if err := dot.Export(graph.ToDOT()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Interactive runs the Intcode program in inputFile on the terminal.
+// Day 13 is played as an arcade game with the joystick on the arrow keys, other days are treated as ASCII programs.
+// Input sent to the program is recorded to recordFile and the input in replayFile is sent before reading from the terminal,
+// either may be empty.
+func Interactive(day, inputFile, recordFile, replayFile string) {
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	program := intcode.ReadProgram(data)
+
+	var in io.Reader = os.Stdin
+	if replayFile != "" {
+		replay, err := os.Open(replayFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer replay.Close()
+		in = io.MultiReader(replay, os.Stdin)
+	}
+
+	var transcript io.Writer
+	if recordFile != "" {
+		record, err := os.Create(recordFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer record.Close()
+		transcript = record
+	}
+
+	if day == "13" {
+		// Without raw mode keys are only read once return is pressed, which still allows a game to be played
+		if restore, err := intcode.RawMode(); err != nil {
+			log.Print("Cannot read single key presses: ", err)
+		} else {
+			defer restore()
+		}
+		err = intcode.RunArcade(program, in, os.Stdout, transcript)
+	} else {
+		err = intcode.RunASCII(program, in, os.Stdout, transcript)
+	}
+	if err != nil {
+		log.Print(err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 	"log"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type caveSystem map[string]map[string]struct{}
@@ -37,6 +39,30 @@ func parseData(data []byte) caveSystem {
 	return passages
 }
 
+// toDOT returns the cave system as a graph with large caves drawn as boxes
+func toDOT(passages caveSystem) *dot.Graph {
+	g := dot.New("caves", false)
+	for a, caves := range passages {
+		for b := range caves {
+			// Passages are held in both directions except those to the start or from the end
+			if _, ok := passages[b][a]; ok && b < a {
+				continue
+			}
+			g.Edge(a, b, nil)
+		}
+	}
+	for _, cave := range []string{"start", "end"} {
+		g.Node(cave, dot.Attrs{"shape": "doublecircle"})
+	}
+	// Every large cave has passages leading from it
+	for cave := range passages {
+		if isUpper(cave) {
+			g.Node(cave, dot.Attrs{"shape": "box"})
+		}
+	}
+	return g
+}
+
 // isUpper returns true if a string is uppercase
 func isUpper(s string) bool {
 	return strings.ToUpper(s) == s
@@ -108,6 +134,11 @@ func Run(inputFile string) {
 	}
 
 	passages := parseData(data)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(passages)); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	p1 := part1(passages)
 	fmt.Println("Part 1:", p1)
@@ -0,0 +1,24 @@
+package day12
+
+import (
+	"testing"
+)
+
+var input = []byte(`start-A
+start-b
+A-c
+A-b
+b-d
+A-end
+b-end`)
+
+func TestToDOT(t *testing.T) {
+	g := toDOT(parseData(input))
+	if got := len(g.Nodes()); got != 6 {
+		t.Errorf("toDOT() has %d nodes, want 6", got)
+	}
+	// Passages between caves are held in both directions but drawn once, as are those only held from start or to end
+	if got := g.Edges(); got != 7 {
+		t.Errorf("toDOT() has %d edges, want 7", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/ocr"
 )
 
 type fold struct {
@@ -68,28 +69,11 @@ func part1(points *map[coordinates.Coord]struct{}, f fold) int {
 	return len(*points)
 }
 
-func part2(points *map[coordinates.Coord]struct{}, folds []fold) {
+func part2(points *map[coordinates.Coord]struct{}, folds []fold) [][]bool {
 	for _, f := range folds {
 		doFold(points, f)
 	}
-
-	finalPoints := []coordinates.Coord{}
-	for k, _ := range *points {
-		finalPoints = append(finalPoints, k)
-	}
-	min, max := coordinates.Range(finalPoints)
-
-	for i := min.Y; i <= max.Y; i++ {
-		for j := min.X; j <= max.X; j++ {
-			c := coordinates.Coord{X: j, Y: i}
-			if _, ok := (*points)[c]; ok {
-				fmt.Print("#")
-			} else {
-				fmt.Print(".")
-			}
-		}
-		fmt.Print("\n")
-	}
+	return ocr.FromCoords(*points)
 }
 
 func Run(inputFile string) {
@@ -103,6 +87,6 @@ func Run(inputFile string) {
 	p1 := part1(&points, folds[0])
 	fmt.Println("Part 1:", p1)
 
-	fmt.Println("Part 2:")
-	part2(&points, folds[1:])
+	p2 := part2(&points, folds[1:])
+	fmt.Println("Part 2:", ocr.Answer(p2))
 }
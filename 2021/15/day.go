@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 type Node struct {
@@ -90,7 +92,8 @@ func getNeighbours(maze [][]int, c coordinates.Coord) []coordinates.Coord {
 	return neighbours
 }
 
-func traverse(maze [][]int) int {
+// traverse returns the lowest total risk of a path from the top left to the bottom right of the maze, and the path
+func traverse(maze [][]int) (int, []coordinates.Coord) {
 	// Initialise priority queue
 	pq := make(PriorityQueue, 1)
 	start := coordinates.Coord{}
@@ -99,13 +102,21 @@ func traverse(maze [][]int) int {
 	heap.Init(&pq)
 
 	explored := map[coordinates.Coord]int{}
+	// previous holds the position each position was best reached from
+	previous := map[coordinates.Coord]coordinates.Coord{}
 
 	for pq.Len() > 0 {
 		// Take next item off priority queue
 		node := heap.Pop(&pq).(*Node)
 
 		if node.c == end {
-			return node.priority
+			path := []coordinates.Coord{end}
+			for c := end; c != start; {
+				c = previous[c]
+				path = append(path, c)
+			}
+			slices.Reverse(path)
+			return node.priority, path
 		}
 
 		// Find all possible next moves and add any unexplored ones to the priority queue
@@ -121,11 +132,12 @@ func traverse(maze [][]int) int {
 				}
 				heap.Push(&pq, &n)
 				explored[move] = newCost
+				previous[move] = node.c
 			}
 		}
 	}
 	log.Fatal("Did not find path")
-	return 0
+	return 0, nil
 }
 
 func extendMaze(maze [][]int, factor int) [][]int {
@@ -153,15 +165,29 @@ func extendMaze(maze [][]int, factor int) [][]int {
 	return newMaze
 }
 
-func part1(maze [][]int) int {
+func part1(maze [][]int) (int, []coordinates.Coord) {
 	return traverse(maze)
 }
 
-func part2(maze [][]int) int {
+func part2(maze [][]int) (int, []coordinates.Coord) {
 	maze = extendMaze(maze, 5)
 	return traverse(maze)
 }
 
+// drawPath draws the path over the risk levels of the maze
+func drawPath(label string, maze [][]int, path []coordinates.Coord) {
+	grid := make([][]rune, len(maze))
+	for i, row := range maze {
+		grid[i] = make([]rune, len(row))
+		for j, risk := range row {
+			grid[i][j] = rune('0' + risk)
+		}
+	}
+	if err := overlay.Draw(label, grid, overlay.Arrows(path)); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func Run(inputFile string) {
 	data, err := ioutil.ReadFile(inputFile)
 	if err != nil {
@@ -170,9 +196,14 @@ func Run(inputFile string) {
 
 	maze := parseData(data)
 
-	p1 := part1(maze)
+	p1, path1 := part1(maze)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(maze)
+	p2, path2 := part2(maze)
 	fmt.Println("Part 2:", p2)
+
+	if overlay.Enabled() {
+		drawPath("part1", maze, path1)
+		drawPath("part2", extendMaze(maze, 5), path2)
+	}
 }
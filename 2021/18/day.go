@@ -252,6 +252,136 @@ func Add(a, b *snailFishNumber) *snailFishNumber {
 	return root
 }
 
+// flatElement is a regular number in a flatNumber and how many pairs it is nested in
+type flatElement struct {
+	value, depth int
+}
+
+// flatNumber is a snailfish number stored as its regular numbers from left to right.
+// The pairs are not stored but follow from the depths, which avoids copying a tree for every addition.
+type flatNumber []flatElement
+
+// flatFromString converts a string in to a flatNumber
+func flatFromString(s string) flatNumber {
+	var n flatNumber
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+
+		// Any integer 0-9
+		default:
+			num, err := strconv.Atoi(string(s[i]))
+			if err != nil {
+				log.Fatal(err)
+			}
+			n = append(n, flatElement{value: num, depth: depth})
+		}
+	}
+	if depth != 0 || len(n) == 0 {
+		log.Fatal("Failed to parse snailfish number ", s)
+	}
+	return n
+}
+
+// String returns the string representation of a flatNumber
+func (n flatNumber) String() string {
+	var b strings.Builder
+	n.write(&b, 0, 0)
+	return b.String()
+}
+
+// write writes the element or pair starting at index i and nested at depth, returning the index after it
+func (n flatNumber) write(b *strings.Builder, i, depth int) int {
+	if n[i].depth == depth {
+		b.WriteString(strconv.Itoa(n[i].value))
+		return i + 1
+	}
+	b.WriteString("[")
+	i = n.write(b, i, depth+1)
+	b.WriteString(",")
+	i = n.write(b, i, depth+1)
+	b.WriteString("]")
+	return i
+}
+
+// Magnitude returns the magnitude of a flatNumber
+func (n flatNumber) Magnitude() int {
+	m, _ := n.magnitude(0, 0)
+	return m
+}
+
+// magnitude returns the magnitude of the element or pair starting at index i and nested at depth, and the index after it
+func (n flatNumber) magnitude(i, depth int) (int, int) {
+	if n[i].depth == depth {
+		return n[i].value, i + 1
+	}
+	lhs, i := n.magnitude(i, depth+1)
+	rhs, i := n.magnitude(i, depth+1)
+	return 3*lhs + 2*rhs, i
+}
+
+// explode will perform an explode action on a flatNumber, returning the updated number
+func (n flatNumber) explode() (flatNumber, bool) {
+	for i := 0; i+1 < len(n); i++ {
+		if n[i].depth > 4 && n[i+1].depth == n[i].depth {
+			if i > 0 {
+				n[i-1].value += n[i].value
+			}
+			if i+2 < len(n) {
+				n[i+2].value += n[i+1].value
+			}
+			n[i] = flatElement{value: 0, depth: n[i].depth - 1}
+			return append(n[:i+1], n[i+2:]...), true
+		}
+	}
+	return n, false
+}
+
+// split will perform a split action on a flatNumber, returning the updated number
+func (n flatNumber) split() (flatNumber, bool) {
+	for i, e := range n {
+		if e.value >= 10 {
+			lhs := flatElement{value: e.value / 2, depth: e.depth + 1}
+			rhs := flatElement{value: e.value - lhs.value, depth: e.depth + 1}
+			n = append(n[:i+1], n[i:]...)
+			n[i], n[i+1] = lhs, rhs
+			return n, true
+		}
+	}
+	return n, false
+}
+
+// reduce will reduce a flatNumber by performing explode and split actions
+func (n flatNumber) reduce() flatNumber {
+	for {
+		var changed bool
+		if n, changed = n.explode(); changed {
+			continue
+		}
+		if n, changed = n.split(); !changed {
+			return n
+		}
+	}
+}
+
+// flatAdd will add two flatNumbers together.
+// Unlike Add the numbers are not modified, so they may be added again.
+func flatAdd(a, b flatNumber) flatNumber {
+	sum := make(flatNumber, 0, len(a)+len(b))
+	for _, e := range a {
+		sum = append(sum, flatElement{value: e.value, depth: e.depth + 1})
+	}
+	for _, e := range b {
+		sum = append(sum, flatElement{value: e.value, depth: e.depth + 1})
+	}
+	return sum.reduce()
+}
+
 // parseData returns the data as a slice of snailFishNumber
 func parseData(data []byte) []*snailFishNumber {
 	lines := strings.Split(
@@ -272,7 +402,8 @@ func part1(numbers []*snailFishNumber) int {
 	return n.Magnitude()
 }
 
-func part2(numbers1, numbers2 []*snailFishNumber) int {
+// part2Tree finds the largest magnitude of any two different numbers using the tree representation
+func part2Tree(numbers1, numbers2 []*snailFishNumber) int {
 	maxMagnitude := 0
 	for i, n1 := range numbers1 {
 		for j, n2 := range numbers2 {
@@ -290,6 +421,30 @@ func part2(numbers1, numbers2 []*snailFishNumber) int {
 	return maxMagnitude
 }
 
+// parseFlatData returns the data as a slice of flatNumber
+func parseFlatData(data []byte) []flatNumber {
+	lines := strings.Split(
+		strings.TrimSuffix(string(data), "\n"), "\n",
+	)
+	numbers := make([]flatNumber, len(lines))
+	for i, line := range lines {
+		numbers[i] = flatFromString(line)
+	}
+	return numbers
+}
+
+func part2(numbers []flatNumber) int {
+	maxMagnitude := 0
+	for i, n1 := range numbers {
+		for j, n2 := range numbers {
+			if i != j {
+				maxMagnitude = max(maxMagnitude, flatAdd(n1, n2).Magnitude())
+			}
+		}
+	}
+	return maxMagnitude
+}
+
 func Run(inputFile string) {
 	data, err := ioutil.ReadFile(inputFile)
 	if err != nil {
@@ -301,9 +456,6 @@ func Run(inputFile string) {
 	p1 := part1(numbers)
 	fmt.Println("Part 1:", p1)
 
-	// Re-read numbers as the existing ones will have been modified
-	numbers1 := parseData(data)
-	numbers2 := parseData(data)
-	p2 := part2(numbers1, numbers2)
+	p2 := part2(parseFlatData(data))
 	fmt.Println("Part 2:", p2)
 }
@@ -3,7 +3,9 @@ package day18
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -144,3 +146,92 @@ func TestExplodeSplit(t *testing.T) {
 		})
 	}
 }
+
+var exampleHomework = []byte(`[[[0,[5,8]],[[1,7],[9,6]]],[[4,[1,2]],[[1,4],2]]]
+[[[5,[2,8]],4],[5,[[9,9],0]]]
+[6,[[[6,2],[5,6]],[[7,6],[4,7]]]]
+[[[6,[0,7]],[0,9]],[4,[9,[9,0]]]]
+[[[7,[6,4]],[3,[1,3]]],[[[5,5],1],9]]
+[[6,[[7,3],[3,2]]],[[[3,8],[5,7]],4]]
+[[[[5,4],[7,7]],8],[[8,3],8]]
+[[9,3],[[9,9],[6,[4,9]]]]
+[[2,[[7,7],7]],[[5,8],[[9,3],[0,2]]]]
+[[[[5,2],5],[8,[3,7]]],[[5,[7,5]],[4,4]]]
+`)
+
+func TestPart1(t *testing.T) {
+	if got, want := part1(parseData(exampleHomework)), 4140; got != want {
+		t.Errorf("Got %d, want %d", got, want)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	if got, want := part2(parseFlatData(exampleHomework)), 3993; got != want {
+		t.Errorf("Got %d, want %d", got, want)
+	}
+	if got, want := part2Tree(parseData(exampleHomework), parseData(exampleHomework)), 3993; got != want {
+		t.Errorf("Got %d from the tree, want %d", got, want)
+	}
+}
+
+// randomNumber returns the string of a random reduced snailfish number
+func randomNumber(r *rand.Rand, depth int) string {
+	if depth > 0 && (depth == 4 || r.Intn(3) == 0) {
+		return fmt.Sprint(r.Intn(10))
+	}
+	return "[" + randomNumber(r, depth+1) + "," + randomNumber(r, depth+1) + "]"
+}
+
+// TestFlatMatchesTree checks the flat representation against the tree for random numbers
+func TestFlatMatchesTree(t *testing.T) {
+	r := rand.New(rand.NewSource(18))
+	for i := 0; i < 1000; i++ {
+		a, b := randomNumber(r, 0), randomNumber(r, 0)
+		flatA, flatB := flatFromString(a), flatFromString(b)
+		if got := flatA.String(); got != a {
+			t.Fatalf("flatFromString(%s).String() = %s", a, got)
+		}
+		if got, want := flatA.Magnitude(), FromString(a).Magnitude(); got != want {
+			t.Fatalf("Magnitude of %s is %d, want %d", a, got, want)
+		}
+		want := Add(FromString(a), FromString(b))
+		got := flatAdd(flatA, flatB)
+		if got.String() != want.String() {
+			t.Fatalf("%s + %s = %s, want %s", a, b, got, want)
+		}
+		if got.Magnitude() != want.Magnitude() {
+			t.Fatalf("Magnitude of %s + %s is %d, want %d", a, b, got.Magnitude(), want.Magnitude())
+		}
+		// Adding must leave the numbers unchanged
+		if flatA.String() != a || flatB.String() != b {
+			t.Fatalf("%s + %s changed the numbers to %s and %s", a, b, flatA, flatB)
+		}
+	}
+}
+
+// benchmarkHomework returns a homework of random numbers the size of a puzzle input
+func benchmarkHomework() []byte {
+	r := rand.New(rand.NewSource(18))
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = randomNumber(r, 0)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func BenchmarkPart2Tree(b *testing.B) {
+	data := benchmarkHomework()
+	numbers1, numbers2 := parseData(data), parseData(data)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		part2Tree(numbers1, numbers2)
+	}
+}
+
+func BenchmarkPart2Flat(b *testing.B) {
+	numbers := parseFlatData(benchmarkHomework())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		part2(numbers)
+	}
+}
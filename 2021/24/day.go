@@ -1,145 +1,186 @@
+// Advent of Code 2021 - Day 24
 package day24
 
 import (
 	"fmt"
-	//	"strings"
+	"io/ioutil"
+	"log"
+
+	"github.com/maze-mapper/advent-of-code/2021/alu"
 )
 
-/*
-type Instruction struct {
-	name, a, b string
-}
+// digits is the number of digits in a model number
+const digits = 14
 
-func parseData(data []byte) []Instruction {
-	lines := strings.Split(
-		strings.TrimSuffix(string(data), "\n"), "\n",
-	)
+// blockTemplate is the part of the MONAD program run for each digit.
+// Only the values a, b and c in the div, add and add instructions change between blocks.
+const blockTemplate = `inp w
+mul x 0
+add x z
+mod x 26
+div z %d
+add x %d
+eql x w
+eql x 0
+mul y 0
+add y 25
+mul y x
+add y 1
+mul z y
+mul y 0
+add y w
+add y %d
+mul y x
+add z y`
 
-	instructions := make([]Instruction, len(lines))
-	for i, line := range lines {
-		parts := strings.Split(line, " ")
-		instructions[i] = Instruction{
-			name: parts[0],
-			a: parts[1],
-		}
-		if parts[0] != "inp" {
-			instructions[i].b = parts[2]
-		}
-	}
-	return instructions
-}
+// Index of the instructions in a block holding the values a, b and c
+var constantIndex = [3]int{4, 5, 15}
 
-// getDigits extracts the digits from a number
-func getDigits(n int, digits *[]int) {
-        if n < 10 {
-                *digits = append(*digits, n)
-        } else {
-                getDigits(n / 10, digits)
-                *digits = append(*digits, n % 10)
-        }
-}
+// extractConstants checks that the program is made of one block per digit following the template and returns the
+// values (a, b, c) from each block
+func extractConstants(program []alu.Instruction) ([][3]int, error) {
+	template, err := alu.Parse([]byte(fmt.Sprintf(blockTemplate, 0, 0, 0)))
+	if err != nil {
+		return nil, err
+	}
 
-// modelNumberToSlice converts an integer in to a slice of ints and also returns a bool to indicate if it is valid
-func modelNumberToSlice(model int) ([]int, bool) {
-	digits := []int{}
-	getDigits(model, &digits)
-	for _, v := range digits {
-		if v == 0 {
-			return digits, false
-		}
+	blocks := alu.Blocks(program)
+	if len(blocks) != digits {
+		return nil, fmt.Errorf("program has %d input blocks, expected %d", len(blocks), digits)
 	}
-	return digits, true
-}
 
-// recurse is a brute force approach that skips zeros
-func recurse(z, depth int, numbers []int) (int, bool) {
-	fmt.Println(z, depth, numbers)
-	if depth >= 14 {
-		if z == 0 {
-			fmt.Println(numbers)
-			return true
+	nums := make([][3]int, len(blocks))
+	for i, block := range blocks {
+		if len(block) != len(template) {
+			return nil, fmt.Errorf("block %d has %d instructions, expected %d", i, len(block), len(template))
+		}
+		for j, inst := range block {
+			want := template[j]
+			if inst.Op != want.Op || inst.A != want.A || inst.B.IsRegister != want.B.IsRegister {
+				return nil, fmt.Errorf("block %d instruction %d is %q, expected %q", i, j, inst, want)
+			}
+			k := -1
+			for n, idx := range constantIndex {
+				if idx == j {
+					k = n
+				}
+			}
+			if k >= 0 {
+				nums[i][k] = inst.B.Value
+			} else if inst.B != want.B {
+				return nil, fmt.Errorf("block %d instruction %d is %q, expected %q", i, j, inst, want)
+			}
 		}
-		return z, false
-	}
-	for i := 9; i > 0; i-- {
-		newZ := f(i, z, nums[depth][0], nums[depth][1], nums[depth][2])
-		newNumbers := make([]int, len(numbers))
-		copy(newNumbers, numbers)
-		newNumbers = append(newNumbers, i)
-		recurse(newZ, depth + 1, newNumbers)
 	}
-	return z, false
+	return nums, nil
 }
 
-func part12() {
-	recurse(0, 0, []int{})
+// pair is a block which pushes a digit on to the stack and the later block which pops it
+type pair struct {
+	push, pop int
+	// diff is the amount the popped digit must be greater than the pushed digit
+	diff int
 }
 
-func part1() int {
-	for model := 99999999999999; model >= 11111111111111; model-- {
-		if digits, ok := modelNumberToSlice(model); ok {
-			fmt.Println(model)
-			z := 0
-			for i, d := range digits {
-				z = f(d, z, nums[i][0], nums[i][1], nums[i][2])
+// pairBlocks matches pushing blocks with popping blocks, treating the z register as a stack
+func pairBlocks(nums [][3]int) ([]pair, error) {
+	stack := []int{}
+	pairs := []pair{}
+	for i, n := range nums {
+		a, b := n[0], n[1]
+		switch a {
+		case 1:
+			// b must be large enough that x never equals w and so the block always pushes
+			if b < 10 {
+				return nil, fmt.Errorf("block %d does not divide z but may not push, b = %d", i, b)
+			}
+			stack = append(stack, i)
+		case 26:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("block %d pops from an empty stack", i)
 			}
-			fmt.Println(z)
-			if z == 0 {
-				return model
+			push := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			d := nums[push][2] + b
+			if d < -8 || d > 8 {
+				return nil, fmt.Errorf("blocks %d and %d differ by %d so cannot both be digits", push, i, d)
 			}
+			pairs = append(pairs, pair{push: push, pop: i, diff: d})
+		default:
+			return nil, fmt.Errorf("block %d divides z by %d, expected 1 or 26", i, a)
 		}
 	}
-	return 0
-}*/
-
-// Numbers extracted from input data
-// Correspond to values (a, b, c) in below function
-var nums = [][3]int{
-	{1, 12, 15},
-	{1, 14, 12},
-	{1, 11, 15},
-	{26, -9, 12},
-	{26, -7, 15},
-	{1, 11, 2},
-	{26, -1, 11},
-	{26, -16, 15},
-	{1, 11, 10},
-	{26, -15, 2},
-	{1, 10, 0},
-	{1, 12, 0},
-	{26, -4, 15},
-	{26, 0, 15},
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%d blocks are never popped from the stack", len(stack))
+	}
+	return pairs, nil
 }
 
-// f is the reverse engineered function for the ALU program
-//   w is the input digit
-//   z is a base 26 stack
-//   a determines if we push (a=1) or pop (a=26) from the stack
-// If pushing we store w + c on the stack
-// To pop we require that the last item on the stack + b = w
-// This gives a relationship between the pushes and pops that can be used to infer the differences between digits pushed and popped.
-// Example using above data:
-//   Consider the pair {1, 11, 15} and {26, -9, 12}
-//   First pushes w_1 + 15 on to the stack
-//   Second pops if w_2 = last item on stack - 9
-//   So w_2 = w_1 + 15 - 9
-//      w_2 = w_1 + 6
-func f(w, z, a, b, c int) int {
-	// Pop from stack z and store result in x with b added to it if a = 26
-	x := (z % 26) + b
-	z /= a
+// modelNumber returns the largest or smallest valid model number using the digit relationships from the block pairs
+func modelNumber(pairs []pair, largest bool) []int {
+	number := make([]int, digits)
+	for _, p := range pairs {
+		if largest {
+			number[p.push] = min(9, 9-p.diff)
+		} else {
+			number[p.push] = max(1, 1-p.diff)
+		}
+		number[p.pop] = number[p.push] + p.diff
+	}
+	return number
+}
 
-	if x != w {
-		// Push w + c to stack z
-		z *= 26
-		z += w + c
+// check runs the program on a model number to confirm that it is valid and returns it as an int
+func check(program []alu.Instruction, number []int) (int, error) {
+	registers, err := alu.Run(program, number)
+	if err != nil {
+		return 0, err
+	}
+	if registers[alu.Z] != 0 {
+		return 0, fmt.Errorf("model number %v leaves z = %d", number, registers[alu.Z])
 	}
+	n := 0
+	for _, d := range number {
+		n = n*10 + d
+	}
+	return n, nil
+}
 
-	return z
+// solve returns the largest and smallest model numbers accepted by the program
+func solve(program []alu.Instruction) (int, int, error) {
+	nums, err := extractConstants(program)
+	if err != nil {
+		return 0, 0, err
+	}
+	pairs, err := pairBlocks(nums)
+	if err != nil {
+		return 0, 0, err
+	}
+	largest, err := check(program, modelNumber(pairs, true))
+	if err != nil {
+		return 0, 0, err
+	}
+	smallest, err := check(program, modelNumber(pairs, false))
+	if err != nil {
+		return 0, 0, err
+	}
+	return largest, smallest, nil
 }
 
 func Run(inputFile string) {
-	fmt.Println("Solved manually for my specific input data")
-	fmt.Println("Part 1:", 94399898949959)
-	fmt.Println("Part 2:", 21176121611511)
+	data, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	program, err := alu.Parse(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	largest, smallest, err := solve(program)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Part 1:", largest)
+	fmt.Println("Part 2:", smallest)
 }
@@ -0,0 +1,163 @@
+package day24
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/2021/alu"
+)
+
+// nums are the values (a, b, c) of each block from a puzzle input
+var nums = [][3]int{
+	{1, 12, 15},
+	{1, 14, 12},
+	{1, 11, 15},
+	{26, -9, 12},
+	{26, -7, 15},
+	{1, 11, 2},
+	{26, -1, 11},
+	{26, -16, 15},
+	{1, 11, 10},
+	{26, -15, 2},
+	{1, 10, 0},
+	{1, 12, 0},
+	{26, -4, 15},
+	{26, 0, 15},
+}
+
+// makeProgram returns the program text for a MONAD with the given block values
+func makeProgram(nums [][3]int) string {
+	blocks := make([]string, len(nums))
+	for i, n := range nums {
+		blocks[i] = fmt.Sprintf(blockTemplate, n[0], n[1], n[2])
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// f is the reverse engineered function for one block of the ALU program
+//
+//	w is the input digit
+//	z is a base 26 stack
+//	a determines if we push (a=1) or pop (a=26) from the stack
+//
+// If pushing we store w + c on the stack
+// To pop we require that the last item on the stack + b = w
+// This gives a relationship between the pushes and pops that can be used to infer the differences between digits pushed and popped.
+// Example:
+//
+//	Consider the pair {1, 11, 15} and {26, -9, 12}
+//	First pushes w_1 + 15 on to the stack
+//	Second pops if w_2 = last item on stack - 9
+//	So w_2 = w_1 + 15 - 9
+//	   w_2 = w_1 + 6
+func f(w, z, a, b, c int) int {
+	// Pop from stack z and store result in x with b added to it if a = 26
+	x := (z % 26) + b
+	z /= a
+
+	if x != w {
+		// Push w + c to stack z
+		z *= 26
+		z += w + c
+	}
+
+	return z
+}
+
+// runF returns the value of z after running f for each block with the digits of a model number
+func runF(nums [][3]int, number []int) int {
+	z := 0
+	for i, n := range nums {
+		z = f(number[i], z, n[0], n[1], n[2])
+	}
+	return z
+}
+
+func TestModelNumberWithF(t *testing.T) {
+	pairs, err := pairBlocks(nums)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, largest := range []bool{true, false} {
+		number := modelNumber(pairs, largest)
+		if z := runF(nums, number); z != 0 {
+			t.Errorf("modelNumber(largest=%t) = %v leaves z = %d, want 0", largest, number, z)
+		}
+		// Breaking the relationship between the digits of any pair leaves something on the stack
+		for _, p := range pairs {
+			broken := slices.Clone(number)
+			if broken[p.pop] < 9 {
+				broken[p.pop]++
+			} else {
+				broken[p.pop]--
+			}
+			if z := runF(nums, broken); z == 0 {
+				t.Errorf("%v breaks the pair of blocks %d and %d but leaves z = 0", broken, p.push, p.pop)
+			}
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	program, err := alu.Parse([]byte(makeProgram(nums)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	largest, smallest, err := solve(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 94399898949959; largest != want {
+		t.Errorf("largest = %d, want %d", largest, want)
+	}
+	if want := 21176121611511; smallest != want {
+		t.Errorf("smallest = %d, want %d", smallest, want)
+	}
+}
+
+func TestF(t *testing.T) {
+	// The reverse engineered function must agree with running each block on the ALU
+	for i, n := range nums {
+		block, err := alu.Parse([]byte(makeProgram([][3]int{n})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, z := range []int{0, 5, 26*7 + 20} {
+			for w := 1; w <= 9; w++ {
+				// Set up z before the block by prefixing instructions
+				setup := []alu.Instruction{{Op: alu.OpAdd, A: alu.Z, B: alu.Operand{Value: z}}}
+				registers, err := alu.Run(append(setup, block...), []int{w})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := f(w, z, n[0], n[1], n[2]), registers[alu.Z]; got != want {
+					t.Errorf("block %d: f(%d, %d) = %d, want %d", i, w, z, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		nums [][3]int
+	}{
+		{name: "too few blocks", nums: nums[:13]},
+		{name: "unbalanced", nums: append(slices.Clone(nums[:13]), [3]int{1, 12, 0})},
+		{name: "digits too far apart", nums: append(slices.Clone(nums[:13]), [3]int{26, 20, 15})},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			program, err := alu.Parse([]byte(makeProgram(tc.nums)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := solve(program); err == nil {
+				t.Errorf("solve() returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Arithmetic Logic Unit
+package alu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Registers of the ALU
+const (
+	W = iota
+	X
+	Y
+	Z
+)
+
+// registerNames maps register names to their index
+var registerNames = map[string]int{"w": W, "x": X, "y": Y, "z": Z}
+
+// Operations known by the ALU
+const (
+	OpInput    = "inp"
+	OpAdd      = "add"
+	OpMultiply = "mul"
+	OpDivide   = "div"
+	OpModulo   = "mod"
+	OpEqual    = "eql"
+)
+
+// ErrNoInput is returned when an input instruction is run with no input left
+var ErrNoInput = errors.New("no input remaining")
+
+// Operand is the second argument of an instruction, either a register or a literal value
+type Operand struct {
+	Register   int
+	Value      int
+	IsRegister bool
+}
+
+// String returns the operand as written in a program
+func (o Operand) String() string {
+	if o.IsRegister {
+		return string("wxyz"[o.Register])
+	}
+	return strconv.Itoa(o.Value)
+}
+
+// Instruction is a single ALU instruction
+type Instruction struct {
+	Op string
+	A  int
+	B  Operand
+}
+
+// String returns the instruction as written in a program
+func (inst Instruction) String() string {
+	if inst.Op == OpInput {
+		return fmt.Sprintf("%s %c", inst.Op, "wxyz"[inst.A])
+	}
+	return fmt.Sprintf("%s %c %s", inst.Op, "wxyz"[inst.A], inst.B)
+}
+
+// parseRegister returns the index of a named register
+func parseRegister(s string) (int, error) {
+	r, ok := registerNames[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown register %q", s)
+	}
+	return r, nil
+}
+
+// Parse returns the instructions in a program, one per line
+func Parse(data []byte) ([]Instruction, error) {
+	lines := strings.Split(
+		strings.TrimSuffix(string(data), "\n"), "\n",
+	)
+
+	program := make([]Instruction, len(lines))
+	for i, line := range lines {
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("line %d: unable to parse %q", i+1, line)
+		}
+		a, err := parseRegister(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		inst := Instruction{Op: parts[0], A: a}
+
+		switch parts[0] {
+		case OpInput:
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("line %d: %s takes one argument", i+1, parts[0])
+			}
+		case OpAdd, OpMultiply, OpDivide, OpModulo, OpEqual:
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("line %d: %s takes two arguments", i+1, parts[0])
+			}
+			if r, err := parseRegister(parts[2]); err == nil {
+				inst.B = Operand{Register: r, IsRegister: true}
+			} else if v, err := strconv.Atoi(parts[2]); err == nil {
+				inst.B = Operand{Value: v}
+			} else {
+				return nil, fmt.Errorf("line %d: invalid operand %q", i+1, parts[2])
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unknown operation %q", i+1, parts[0])
+		}
+		program[i] = inst
+	}
+	return program, nil
+}
+
+// Run runs the program with the given input values and returns the final register values
+func Run(program []Instruction, input []int) ([4]int, error) {
+	var registers [4]int
+	for i, inst := range program {
+		b := inst.B.Value
+		if inst.B.IsRegister {
+			b = registers[inst.B.Register]
+		}
+		a := &registers[inst.A]
+
+		switch inst.Op {
+		case OpInput:
+			if len(input) == 0 {
+				return registers, fmt.Errorf("instruction %d: %w", i, ErrNoInput)
+			}
+			*a = input[0]
+			input = input[1:]
+		case OpAdd:
+			*a += b
+		case OpMultiply:
+			*a *= b
+		case OpDivide:
+			if b == 0 {
+				return registers, fmt.Errorf("instruction %d: division by zero", i)
+			}
+			*a /= b
+		case OpModulo:
+			if *a < 0 || b <= 0 {
+				return registers, fmt.Errorf("instruction %d: modulo of %d by %d", i, *a, b)
+			}
+			*a %= b
+		case OpEqual:
+			if *a == b {
+				*a = 1
+			} else {
+				*a = 0
+			}
+		}
+	}
+	return registers, nil
+}
+
+// Blocks splits a program in to blocks which each start with an input instruction
+func Blocks(program []Instruction) [][]Instruction {
+	blocks := [][]Instruction{}
+	for i, inst := range program {
+		if inst.Op == OpInput || i == 0 {
+			blocks = append(blocks, []Instruction{})
+		}
+		blocks[len(blocks)-1] = append(blocks[len(blocks)-1], inst)
+	}
+	return blocks
+}
@@ -0,0 +1,77 @@
+package alu
+
+import (
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		input   []int
+		want    [4]int
+	}{
+		{
+			name:    "negate",
+			program: "inp x\nmul x -1",
+			input:   []int{7},
+			want:    [4]int{0, -7, 0, 0},
+		},
+		{
+			name:    "three times larger",
+			program: "inp z\ninp x\nmul z 3\neql z x",
+			input:   []int{2, 6},
+			want:    [4]int{0, 6, 0, 1},
+		},
+		{
+			name:    "binary",
+			program: "inp w\nadd z w\nmod z 2\ndiv w 2\nadd y w\nmod y 2\ndiv w 2\nadd x w\nmod x 2\ndiv w 2\nmod w 2",
+			input:   []int{11},
+			want:    [4]int{1, 0, 1, 1},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			program, err := Parse([]byte(tc.program))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Run(program, tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Run(%q, %v) = %v, want %v", tc.program, tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name, program string
+	}{
+		{name: "no input", program: "inp w\ninp x"},
+		{name: "divide by zero", program: "inp w\ndiv w 0"},
+		{name: "negative modulo", program: "inp w\nmul w -1\nmod w 2"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			program, err := Parse([]byte(tc.program))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Run(program, []int{1}); err == nil {
+				t.Errorf("Run(%q) returned no error", tc.program)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, program := range []string{"inp a", "add x", "jmp x 1", "add x q", "inp w x"} {
+		if _, err := Parse([]byte(program)); err == nil {
+			t.Errorf("Parse(%q) returned no error", program)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// BITS packet codec
+package bits
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTruncated is returned when the data ends part way through a packet
+var ErrTruncated = errors.New("packet is truncated")
+
+// TypeID is the type of a packet, which is a literal value or the operation of an operator
+type TypeID uint8
+
+// Packet types
+const (
+	TypeSum TypeID = iota
+	TypeProduct
+	TypeMinimum
+	TypeMaximum
+	TypeLiteral
+	TypeGreaterThan
+	TypeLessThan
+	TypeEqual
+)
+
+// typeNames are the names of the packet types when written as S-expressions
+var typeNames = map[TypeID]string{
+	TypeSum:         "sum",
+	TypeProduct:     "product",
+	TypeMinimum:     "min",
+	TypeMaximum:     "max",
+	TypeGreaterThan: "gt",
+	TypeLessThan:    "lt",
+	TypeEqual:       "eq",
+}
+
+// LengthType is how an operator packet gives the length of its sub-packets
+type LengthType uint8
+
+// Length types of operator packets
+const (
+	// LengthBits gives the total number of bits in the sub-packets in 15 bits
+	LengthBits LengthType = iota
+	// LengthPackets gives the number of sub-packets in 11 bits
+	LengthPackets
+)
+
+// Field sizes in bits
+const (
+	versionBits      = 3
+	typeIDBits       = 3
+	lengthTypeBits   = 1
+	lengthInBits     = 15
+	lengthInPackets  = 11
+	literalGroupBits = 4
+	maxLiteralGroups = 16
+	maxVersion       = 1<<versionBits - 1
+	maxLengthBits    = 1<<lengthInBits - 1
+	maxLengthPackets = 1<<lengthInPackets - 1
+	literalGroupMask = 1<<literalGroupBits - 1
+)
+
+// Packet is a BITS packet, either a literal value or an operator applied to its sub-packets
+type Packet struct {
+	Version uint8
+	TypeID  TypeID
+	// Value is the value of a literal packet
+	Value uint64
+	// LengthType is how an operator packet is encoded
+	LengthType LengthType
+	// SubPackets are the operands of an operator packet
+	SubPackets []*Packet
+}
+
+// Literal returns a literal packet
+func Literal(version uint8, value uint64) *Packet {
+	return &Packet{Version: version, TypeID: TypeLiteral, Value: value}
+}
+
+// Operator returns an operator packet which is encoded with the given length type
+func Operator(version uint8, typeID TypeID, lengthType LengthType, subPackets ...*Packet) *Packet {
+	return &Packet{Version: version, TypeID: typeID, LengthType: lengthType, SubPackets: subPackets}
+}
+
+// IsLiteral returns whether the packet is a literal value
+func (p *Packet) IsLiteral() bool {
+	return p.TypeID == TypeLiteral
+}
+
+// VersionSum returns the sum of the versions of the packet and all of its sub-packets
+func (p *Packet) VersionSum() int {
+	sum := int(p.Version)
+	for _, sub := range p.SubPackets {
+		sum += sub.VersionSum()
+	}
+	return sum
+}
+
+// Eval returns the value of the packet.
+// An error is returned if an operator has the wrong number of sub-packets for its type.
+func (p *Packet) Eval() (uint64, error) {
+	if p.IsLiteral() {
+		return p.Value, nil
+	}
+	values := make([]uint64, len(p.SubPackets))
+	for i, sub := range p.SubPackets {
+		v, err := sub.Eval()
+		if err != nil {
+			return 0, err
+		}
+		values[i] = v
+	}
+
+	switch p.TypeID {
+	case TypeSum:
+		var sum uint64
+		for _, v := range values {
+			sum += v
+		}
+		return sum, nil
+	case TypeProduct:
+		var product uint64 = 1
+		for _, v := range values {
+			product *= v
+		}
+		return product, nil
+	case TypeMinimum, TypeMaximum:
+		if len(values) == 0 {
+			return 0, fmt.Errorf("%s packet has no sub-packets", typeNames[p.TypeID])
+		}
+		result := values[0]
+		for _, v := range values[1:] {
+			if p.TypeID == TypeMinimum {
+				result = min(result, v)
+			} else {
+				result = max(result, v)
+			}
+		}
+		return result, nil
+	case TypeGreaterThan, TypeLessThan, TypeEqual:
+		if len(values) != 2 {
+			return 0, fmt.Errorf("%s packet has %d sub-packets, want 2", typeNames[p.TypeID], len(values))
+		}
+		var result bool
+		switch p.TypeID {
+		case TypeGreaterThan:
+			result = values[0] > values[1]
+		case TypeLessThan:
+			result = values[0] < values[1]
+		case TypeEqual:
+			result = values[0] == values[1]
+		}
+		if result {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown packet type %d", p.TypeID)
+}
+
+// String returns the packet as an S-expression on a single line, such as "(sum 1 (product 2 3))"
+func (p *Packet) String() string {
+	if p.IsLiteral() {
+		return fmt.Sprint(p.Value)
+	}
+	parts := []string{typeNames[p.TypeID]}
+	for _, sub := range p.SubPackets {
+		parts = append(parts, sub.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Pretty returns the packet as an S-expression with one packet per line, indented by depth and annotated with the
+// version and length type of each packet
+func (p *Packet) Pretty() string {
+	var sb strings.Builder
+	p.pretty(&sb, 0)
+	return sb.String()
+}
+
+// pretty writes the packet at a depth of indentation
+func (p *Packet) pretty(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	if p.IsLiteral() {
+		fmt.Fprintf(sb, "%d ; v%d\n", p.Value, p.Version)
+		return
+	}
+	length := "bits"
+	if p.LengthType == LengthPackets {
+		length = "packets"
+	}
+	fmt.Fprintf(sb, "(%s ; v%d %s\n", typeNames[p.TypeID], p.Version, length)
+	for _, sub := range p.SubPackets {
+		sub.pretty(sb, depth+1)
+	}
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(")\n")
+}
+
+// reader reads a stream of bits, most significant bit first
+type reader struct {
+	data []byte
+	pos  int
+}
+
+// read returns the next n bits as an unsigned integer
+func (r *reader) read(n int) (uint64, error) {
+	if r.pos+n > 8*len(r.data) {
+		return 0, ErrTruncated
+	}
+	var result uint64
+	for i := 0; i < n; i++ {
+		bit := r.data[r.pos/8] >> (7 - r.pos%8) & 1
+		result = result<<1 | uint64(bit)
+		r.pos++
+	}
+	return result, nil
+}
+
+// Decode returns the outermost packet in the data.
+// Bits after the packet are ignored, as they are padding.
+func Decode(data []byte) (*Packet, error) {
+	r := &reader{data: data}
+	return decode(r)
+}
+
+// DecodeHex returns the outermost packet in hexadecimal text
+func DecodeHex(s string) (*Packet, error) {
+	data, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// decode reads a packet and its sub-packets
+func decode(r *reader) (*Packet, error) {
+	version, err := r.read(versionBits)
+	if err != nil {
+		return nil, err
+	}
+	typeID, err := r.read(typeIDBits)
+	if err != nil {
+		return nil, err
+	}
+	p := &Packet{Version: uint8(version), TypeID: TypeID(typeID)}
+
+	if p.IsLiteral() {
+		for groups := 1; ; groups++ {
+			more, err := r.read(1)
+			if err != nil {
+				return nil, err
+			}
+			group, err := r.read(literalGroupBits)
+			if err != nil {
+				return nil, err
+			}
+			// Shifting in another group must not lose any bits of the value
+			if groups > maxLiteralGroups && p.Value>>(64-literalGroupBits) != 0 {
+				return nil, fmt.Errorf("literal at bit %d does not fit in 64 bits", r.pos)
+			}
+			p.Value = p.Value<<literalGroupBits | group
+			if more == 0 {
+				return p, nil
+			}
+		}
+	}
+
+	lengthType, err := r.read(lengthTypeBits)
+	if err != nil {
+		return nil, err
+	}
+	p.LengthType = LengthType(lengthType)
+	switch p.LengthType {
+	case LengthBits:
+		length, err := r.read(lengthInBits)
+		if err != nil {
+			return nil, err
+		}
+		end := r.pos + int(length)
+		for r.pos < end {
+			sub, err := decode(r)
+			if err != nil {
+				return nil, err
+			}
+			p.SubPackets = append(p.SubPackets, sub)
+		}
+		if r.pos != end {
+			return nil, fmt.Errorf("sub-packets end at bit %d, want %d", r.pos, end)
+		}
+	case LengthPackets:
+		count, err := r.read(lengthInPackets)
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < count; i++ {
+			sub, err := decode(r)
+			if err != nil {
+				return nil, err
+			}
+			p.SubPackets = append(p.SubPackets, sub)
+		}
+	}
+	return p, nil
+}
+
+// writer builds a stream of bits, most significant bit first
+type writer struct {
+	data []byte
+	pos  int
+}
+
+// write appends the lowest n bits of a value
+func (w *writer) write(value uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.pos%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if value>>i&1 == 1 {
+			w.data[w.pos/8] |= 0x80 >> (w.pos % 8)
+		}
+		w.pos++
+	}
+}
+
+// append writes the bits of another stream
+func (w *writer) append(other *writer) {
+	for i := 0; i < other.pos; i++ {
+		w.write(uint64(other.data[i/8]>>(7-i%8)&1), 1)
+	}
+}
+
+// Encode returns the packet as bytes, padded with zero bits to a whole number of bytes.
+// Literals are written with as few groups as possible and operators with their length type.
+func Encode(p *Packet) ([]byte, error) {
+	w := &writer{}
+	if err := encode(w, p); err != nil {
+		return nil, err
+	}
+	return w.data, nil
+}
+
+// EncodeHex returns the packet as upper case hexadecimal text
+func EncodeHex(p *Packet) (string, error) {
+	data, err := Encode(p)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(data)), nil
+}
+
+// encode writes a packet and its sub-packets
+func encode(w *writer, p *Packet) error {
+	if p.Version > maxVersion {
+		return fmt.Errorf("version %d does not fit in %d bits", p.Version, versionBits)
+	}
+	if p.TypeID > TypeEqual {
+		return fmt.Errorf("unknown packet type %d", p.TypeID)
+	}
+	w.write(uint64(p.Version), versionBits)
+	w.write(uint64(p.TypeID), typeIDBits)
+
+	if p.IsLiteral() {
+		if len(p.SubPackets) != 0 {
+			return fmt.Errorf("literal packet has %d sub-packets", len(p.SubPackets))
+		}
+		groups := 1
+		for groups < maxLiteralGroups && p.Value>>(groups*literalGroupBits) != 0 {
+			groups++
+		}
+		for i := groups - 1; i >= 0; i-- {
+			var more uint64
+			if i > 0 {
+				more = 1
+			}
+			w.write(more, 1)
+			w.write(p.Value>>(i*literalGroupBits)&literalGroupMask, literalGroupBits)
+		}
+		return nil
+	}
+
+	subs := &writer{}
+	for _, sub := range p.SubPackets {
+		if err := encode(subs, sub); err != nil {
+			return err
+		}
+	}
+	switch p.LengthType {
+	case LengthBits:
+		if subs.pos > maxLengthBits {
+			return fmt.Errorf("sub-packets are %d bits long, more than fits in %d bits", subs.pos, lengthInBits)
+		}
+		w.write(uint64(LengthBits), lengthTypeBits)
+		w.write(uint64(subs.pos), lengthInBits)
+	case LengthPackets:
+		if len(p.SubPackets) > maxLengthPackets {
+			return fmt.Errorf("%d sub-packets are more than fit in %d bits", len(p.SubPackets), lengthInPackets)
+		}
+		w.write(uint64(LengthPackets), lengthTypeBits)
+		w.write(uint64(len(p.SubPackets)), lengthInPackets)
+	default:
+		return fmt.Errorf("unknown length type %d", p.LengthType)
+	}
+	w.append(subs)
+	return nil
+}
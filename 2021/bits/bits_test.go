@@ -0,0 +1,227 @@
+package bits
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVersionSum(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{input: "8A004A801A8002F478", want: 16},
+		{input: "620080001611562C8802118E34", want: 12},
+		{input: "C0015000016115A2E0802F182340", want: 23},
+		{input: "A0016C880162017C3686B18A3D4780", want: 31},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p, err := DecodeHex(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.VersionSum(); got != tc.want {
+				t.Errorf("VersionSum() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint64
+		sexpr string
+	}{
+		{input: "C200B40A82", want: 3, sexpr: "(sum 1 2)"},
+		{input: "04005AC33890", want: 54, sexpr: "(product 6 9)"},
+		{input: "880086C3E88112", want: 7, sexpr: "(min 7 8 9)"},
+		{input: "CE00C43D881120", want: 9, sexpr: "(max 7 8 9)"},
+		{input: "D8005AC2A8F0", want: 1, sexpr: "(lt 5 15)"},
+		{input: "F600BC2D8F", want: 0, sexpr: "(gt 5 15)"},
+		{input: "9C005AC2F8F0", want: 0, sexpr: "(eq 5 15)"},
+		{input: "9C0141080250320F1802104A08", want: 1, sexpr: "(eq (sum 1 3) (product 2 2))"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p, err := DecodeHex(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Eval()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Eval() = %d, want %d", got, tc.want)
+			}
+			if s := p.String(); s != tc.sexpr {
+				t.Errorf("String() = %s, want %s", s, tc.sexpr)
+			}
+		})
+	}
+}
+
+func TestEvalError(t *testing.T) {
+	p := Operator(0, TypeGreaterThan, LengthPackets, Literal(0, 1))
+	if _, err := p.Eval(); err == nil {
+		t.Errorf("Eval() of %s returned no error", p)
+	}
+}
+
+func TestPretty(t *testing.T) {
+	p, err := DecodeHex("38006F45291200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "(lt ; v1 bits\n  10 ; v6\n  20 ; v2\n)\n"
+	if got := p.Pretty(); got != want {
+		t.Errorf("Pretty() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeHex(t *testing.T) {
+	tests := []struct {
+		name   string
+		packet *Packet
+		want   string
+	}{
+		{
+			name:   "literal",
+			packet: Literal(6, 2021),
+			want:   "D2FE28",
+		},
+		{
+			name:   "length in bits",
+			packet: Operator(1, TypeLessThan, LengthBits, Literal(6, 10), Literal(2, 20)),
+			want:   "38006F45291200",
+		},
+		{
+			name:   "length in packets",
+			packet: Operator(7, TypeMaximum, LengthPackets, Literal(2, 1), Literal(4, 2), Literal(1, 3)),
+			want:   "EE00D40C823060",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := EncodeHex(tc.packet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("EncodeHex(%s) = %s, want %s", tc.packet, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	tests := []struct {
+		name   string
+		packet *Packet
+	}{
+		{name: "version", packet: Literal(8, 1)},
+		{name: "literal with sub-packets", packet: &Packet{TypeID: TypeLiteral, SubPackets: []*Packet{Literal(0, 1)}}},
+		{name: "length type", packet: Operator(0, TypeSum, 2, Literal(0, 1))},
+		{name: "too many packets", packet: Operator(0, TypeSum, LengthPackets, make([]*Packet, maxLengthPackets+1)...)},
+	}
+	for i := range tests[3].packet.SubPackets {
+		tests[3].packet.SubPackets[i] = Literal(0, 0)
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Encode(tc.packet); err == nil {
+				t.Errorf("Encode(%s) returned no error", tc.packet)
+			}
+		})
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	if _, err := DecodeHex("38006F452912"); !errors.Is(err, ErrTruncated) {
+		t.Errorf("DecodeHex() error = %v, want %v", err, ErrTruncated)
+	}
+}
+
+// FuzzRoundTrip checks that any packet which decodes is encoded back to the same packet
+func FuzzRoundTrip(f *testing.F) {
+	for _, s := range []string{"D2FE28", "38006F45291200", "EE00D40C823060", "A0016C880162017C3686B18A3D4780"} {
+		p, err := DecodeHex(s)
+		if err != nil {
+			f.Fatal(err)
+		}
+		data, err := Encode(p)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := Decode(data)
+		if err != nil {
+			return
+		}
+		encoded, err := Encode(p)
+		if err != nil {
+			t.Fatalf("Encode(%s) error: %v", p, err)
+		}
+		got, err := Decode(encoded)
+		if err != nil {
+			t.Fatalf("Decode(Encode(%s)) error: %v", p, err)
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("Decode(Encode(%s)) = %s", p.Pretty(), got.Pretty())
+		}
+	})
+}
+
+// generate builds a packet from fuzzer input, returning the packet and the unused input
+func generate(input []byte, depth int) (*Packet, []byte) {
+	next := func() byte {
+		if len(input) == 0 {
+			return 0
+		}
+		b := input[0]
+		input = input[1:]
+		return b
+	}
+	b := next()
+	version := b & 7
+	typeID := TypeID(b >> 3 & 7)
+	if typeID == TypeLiteral || depth > 3 || len(input) == 0 {
+		var value uint64
+		for i := 0; i < int(b>>6)*3; i++ {
+			value = value<<8 | uint64(next())
+		}
+		return Literal(version, value), input
+	}
+	p := Operator(version, typeID, LengthType(b>>6&1))
+	for n := int(next() % 4); n > 0; n-- {
+		var sub *Packet
+		sub, input = generate(input, depth+1)
+		p.SubPackets = append(p.SubPackets, sub)
+	}
+	return p, input
+}
+
+// FuzzEncode checks that generated packets decode to the packet which was encoded
+func FuzzEncode(f *testing.F) {
+	f.Add([]byte{0x24, 0x01, 0x02})
+	f.Add([]byte{0x4a, 0x03, 0x20, 0xe4, 0xff, 0xff, 0xff, 0xff, 0x61, 0x07})
+	f.Fuzz(func(t *testing.T, input []byte) {
+		p, _ := generate(input, 0)
+		data, err := Encode(p)
+		if err != nil {
+			t.Fatalf("Encode(%s) error: %v", p, err)
+		}
+		got, err := Decode(data)
+		if err != nil {
+			t.Fatalf("Decode(Encode(%s)) error: %v", p, err)
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("Decode(Encode(%s)) = %s", p.Pretty(), got.Pretty())
+		}
+	})
+}
@@ -6,102 +6,41 @@ import (
 	"io/ioutil"
 	"log"
 	"math"
-	"strconv"
-	"strings"
-)
-
-type treeNode struct {
-	parent   *treeNode
-	children map[string]*treeNode
-	files    map[string]int
-}
-
-func newTreeNode(parent *treeNode) *treeNode {
-	return &treeNode{
-		parent:   parent,
-		children: map[string]*treeNode{},
-		files:    map[string]int{},
-	}
-}
 
-func parseInput(data []byte) *treeNode {
-	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
-	// First line is root directory.
-	if lines[0] != "$ cd /" {
-		log.Fatal("Expecting first line to be \"$ cd /\"")
-	}
-	root := newTreeNode(nil)
-	n := root
-	for i := 1; i < len(lines); i++ {
-		if strings.HasPrefix(lines[i], "$ cd ") {
-			dir := strings.TrimPrefix(lines[i], "$ cd ")
-			if dir == ".." {
-				n = n.parent
-			} else {
-				n = n.children[dir]
-			}
-		} else if lines[i] == "$ ls" {
-			i += 1
-			for ; i < len(lines) && !strings.HasPrefix(lines[i], "$"); i++ {
-
-				if strings.HasPrefix(lines[i], "dir ") {
-					dir := strings.TrimPrefix(lines[i], "dir ")
-					if _, ok := n.children[dir]; !ok {
-						n.children[dir] = newTreeNode(n)
-					}
-				} else {
-					parts := strings.Split(lines[i], " ")
-					size, err := strconv.Atoi(parts[0])
-					if err != nil {
-						log.Fatal(err)
-					}
-					n.files[parts[1]] = size
-				}
-			}
-			i -= 1
-		}
-
-	}
-	return root
-}
+	"github.com/maze-mapper/advent-of-code/2022/vfs"
+)
 
-func calculateDirSizes(path string, node *treeNode, sizes map[string]int) {
-	totalSize := 0
-	for _, size := range node.files {
-		totalSize += size
-	}
-	for k, v := range node.children {
-		childPath := path + k + "/"
-		calculateDirSizes(childPath, v, sizes)
-		totalSize += sizes[childPath]
-	}
-	sizes[path] = totalSize
+func parseInput(data []byte) (*vfs.FS, error) {
+	return vfs.Replay(string(data))
 }
 
-func part1(sizes map[string]int) int {
+func part1(usage []vfs.Usage) int {
 	sum := 0
-	for _, v := range sizes {
-		if v <= 100000 {
-			sum += v
+	for _, u := range usage {
+		if u.Size <= 100000 {
+			sum += u.Size
 		}
 	}
 	return sum
 }
 
-func part2(sizes map[string]int) int {
+// part2 returns the size and path of the smallest directory which frees enough space when deleted
+func part2(usage []vfs.Usage, used int) (int, string) {
 	totalSpace := 70000000
 	requiredSpace := 30000000
-	freeSpace := totalSpace - sizes["/"]
+	freeSpace := totalSpace - used
 	minSpaceToDelete := requiredSpace - freeSpace
 
 	dirSize := int(math.MaxInt)
-	for _, v := range sizes {
-		if v >= minSpaceToDelete && v < dirSize {
-			dirSize = v
+	var dirPath string
+	for _, u := range usage {
+		if u.Size >= minSpaceToDelete && u.Size < dirSize {
+			dirSize = u.Size
+			dirPath = u.Path
 		}
 	}
 
-	return dirSize
+	return dirSize, dirPath
 }
 
 func Run(inputFile string) {
@@ -109,13 +48,21 @@ func Run(inputFile string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	directoryTree := parseInput(data)
-	sizes := map[string]int{}
-	calculateDirSizes("/", directoryTree, sizes)
+	fs, err := parseInput(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	usage := fs.Usage()
 
-	p1 := part1(sizes)
+	p1 := part1(usage)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(sizes)
+	p2, path := part2(usage, fs.Root.Size())
 	fmt.Println("Part 2:", p2)
+
+	if vfs.Enabled() {
+		if err := vfs.Export(fs, path); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
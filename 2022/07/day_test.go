@@ -0,0 +1,54 @@
+package day7
+
+import (
+	"testing"
+)
+
+var input = []byte(`$ cd /
+$ ls
+dir a
+14848514 b.txt
+8504156 c.dat
+dir d
+$ cd a
+$ ls
+dir e
+29116 f
+2557 g
+62596 h.lst
+$ cd e
+$ ls
+584 i
+$ cd ..
+$ cd ..
+$ cd d
+$ ls
+4060174 j
+8033020 d.log
+5626152 d.ext
+7214296 k
+`)
+
+func TestPart1(t *testing.T) {
+	want := 95437
+	fs, err := parseInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := part1(fs.Usage())
+	if got != want {
+		t.Errorf("part1() = %d, want %d", got, want)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	want, wantPath := 24933642, "/d"
+	fs, err := parseInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, path := part2(fs.Usage(), fs.Root.Size())
+	if got != want || path != wantPath {
+		t.Errorf("part2() = %d, %s, want %d, %s", got, path, want, wantPath)
+	}
+}
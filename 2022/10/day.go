@@ -5,80 +5,71 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"strconv"
-	"strings"
+
+	"github.com/maze-mapper/advent-of-code/machine"
+	"github.com/maze-mapper/advent-of-code/ocr"
 )
 
-type instruction struct {
-	cycles int
-	value  int
-}
+// instructionSet holds the instructions of the CPU, which has a single register X
+var instructionSet = machine.MustNewSet(
+	[]string{"x"},
+	machine.Op{
+		Name:   "noop",
+		Cycles: 1,
+		Exec: func(m *machine.Machine, args []int) error {
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "addx",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Cycles:   2,
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Registers[0] += args[0]
+			return nil
+		},
+	},
+)
 
-func parseInput(data []byte) []instruction {
-	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
-	instructions := make([]instruction, len(lines))
-	for i, line := range lines {
-		if line == "noop" {
-			instructions[i] = instruction{
-				cycles: 1,
-			}
-		} else {
-			parts := strings.Split(line, " ")
-			val, err := strconv.Atoi(parts[1])
-			if err != nil {
-				log.Fatal(err)
-			}
-			instructions[i] = instruction{
-				cycles: 2,
-				value:  val,
-			}
-		}
+// runCPU runs the program with the X register starting at 1, calling f during every cycle
+func runCPU(program []machine.Instruction, f func(cycle, x int)) error {
+	m := machine.New(instructionSet, program)
+	m.Registers[0] = 1
+	m.OnCycle = func(m *machine.Machine) {
+		f(m.Cycles, m.Registers[0])
 	}
-	return instructions
+	return m.Run()
 }
 
-func part1(instructions []instruction) int {
+func part1(program []machine.Instruction) (int, error) {
 	maxCycles := 220
 	signalStrength := 0
-	x := 1
-	cycle := 0
-	for _, ins := range instructions {
-		for c := 0; c < ins.cycles; c++ {
-			cycle += 1
-			if cycle%40 == 20 && cycle <= maxCycles {
-				signalStrength += cycle * x
-			}
+	err := runCPU(program, func(cycle, x int) {
+		if cycle%40 == 20 && cycle <= maxCycles {
+			signalStrength += cycle * x
 		}
-		x += ins.value
-	}
-	return signalStrength
+	})
+	return signalStrength, err
 }
 
-func part2(instructions []instruction) {
+func part2(program []machine.Instruction) ([][]bool, error) {
 	rows := 6
 	columns := 40
 	maxCycles := rows * columns
 
-	x := 1
-	cycle := 0
-	for _, ins := range instructions {
-		if cycle >= maxCycles {
-			break
-		}
-		for c := 0; c < ins.cycles; c++ {
-			currentCol := cycle % columns
-			cycle += 1
-			if (currentCol <= x+1) && (currentCol >= x-1) {
-				fmt.Print("#")
-			} else {
-				fmt.Print(".")
-			}
-			if currentCol == columns-1 {
-				fmt.Print("\n")
-			}
-		}
-		x += ins.value
+	screen := make([][]bool, rows)
+	for i := range screen {
+		screen[i] = make([]bool, columns)
 	}
+	err := runCPU(program, func(cycle, x int) {
+		if cycle > maxCycles {
+			return
+		}
+		currentRow := (cycle - 1) / columns
+		currentCol := (cycle - 1) % columns
+		screen[currentRow][currentCol] = (currentCol <= x+1) && (currentCol >= x-1)
+	})
+	return screen, err
 }
 
 func Run(inputFile string) {
@@ -86,11 +77,20 @@ func Run(inputFile string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	instructions := parseInput(data)
+	program, err := instructionSet.Parse(data)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	p1 := part1(instructions)
+	p1, err := part1(program)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 1:", p1)
 
-	fmt.Println("Part 2:")
-	part2(instructions)
+	p2, err := part2(program)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Part 2:", ocr.Answer(p2))
 }
@@ -8,8 +8,10 @@ import (
 	"io/ioutil"
 	"log"
 	"math"
+	"slices"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 func parseInput(data []byte) ([][]uint8, coordinates.Coord, coordinates.Coord) {
@@ -107,7 +109,9 @@ func (pq *PriorityQueue) Pop() interface{} {
 	return node
 }
 
-func AStar(hill [][]uint8, start coordinates.Coord, end coordinates.Coord) (int, bool) {
+// AStar returns the fewest steps from the start to the end of the hill and the path taken, or false if the end cannot
+// be reached.
+func AStar(hill [][]uint8, start coordinates.Coord, end coordinates.Coord) (int, []coordinates.Coord, bool) {
 	// Initialise priority queue
 	pq := make(PriorityQueue, 1)
 	startNode := &Node{
@@ -122,12 +126,20 @@ func AStar(hill [][]uint8, start coordinates.Coord, end coordinates.Coord) (int,
 	visited := map[coordinates.Coord]int{
 		start: 0,
 	}
+	// previous holds the position each position was best reached from.
+	previous := map[coordinates.Coord]coordinates.Coord{}
 
 	for pq.Len() > 0 {
 		node := heap.Pop(&pq).(*Node)
 
 		if node.c == end {
-			return node.step, true
+			path := []coordinates.Coord{end}
+			for c := end; c != start; {
+				c = previous[c]
+				path = append(path, c)
+			}
+			slices.Reverse(path)
+			return node.step, path, true
 		}
 
 		moves := neighbours(hill, node.c)
@@ -140,30 +152,51 @@ func AStar(hill [][]uint8, start coordinates.Coord, end coordinates.Coord) (int,
 					priority: nextStep + coordinates.ManhattanDistance(move, end),
 				})
 				visited[move] = nextStep
+				previous[move] = node.c
 			}
 
 		}
 	}
-	return 0, false
+	return 0, nil, false
 }
 
-func part1(hill [][]uint8, start coordinates.Coord, end coordinates.Coord) int {
-	steps, _ := AStar(hill, start, end)
-	return steps
+func part1(hill [][]uint8, start coordinates.Coord, end coordinates.Coord) (int, []coordinates.Coord) {
+	steps, path, _ := AStar(hill, start, end)
+	return steps, path
 }
 
-func part2(hill [][]uint8, end coordinates.Coord) int {
+func part2(hill [][]uint8, end coordinates.Coord) (int, []coordinates.Coord) {
 	minSteps := int(math.MaxInt)
+	var minPath []coordinates.Coord
 	for y, row := range hill {
 		for x, p := range row {
 			if p == 0 {
-				if steps, ok := AStar(hill, coordinates.Coord{X: x, Y: y}, end); ok && steps < minSteps {
+				if steps, path, ok := AStar(hill, coordinates.Coord{X: x, Y: y}, end); ok && steps < minSteps {
 					minSteps = steps
+					minPath = path
 				}
 			}
 		}
 	}
-	return minSteps
+	return minSteps, minPath
+}
+
+// drawPath draws the path over the heights of the hill.
+func drawPath(label string, hill [][]uint8, path []coordinates.Coord) {
+	grid := make([][]rune, len(hill))
+	for i, row := range hill {
+		grid[i] = make([]rune, len(row))
+		for j, height := range row {
+			grid[i][j] = rune('a' + height)
+		}
+	}
+	marks := overlay.Arrows(path)
+	if len(path) > 0 {
+		marks[path[len(path)-1]] = 'E'
+	}
+	if err := overlay.Draw(label, grid, marks); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func Run(inputFile string) {
@@ -173,9 +206,14 @@ func Run(inputFile string) {
 	}
 	hill, start, end := parseInput(data)
 
-	p1 := part1(hill, start, end)
+	p1, path1 := part1(hill, start, end)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(hill, end)
+	p2, path2 := part2(hill, end)
 	fmt.Println("Part 2:", p2)
+
+	if overlay.Enabled() {
+		drawPath("part1", hill, path1)
+		drawPath("part2", hill, path2)
+	}
 }
@@ -4,26 +4,55 @@ import (
 	"testing"
 )
 
-func TestCompare(t *testing.T) {
-	tests := []struct {
-		left, right []any
-		want        int
-	}{
-		{left: []any{1, 1, 3, 1, 1}, right: []any{1, 1, 5, 1, 1}, want: correctOrder},
-		{left: []any{[]any{1}, []any{2, 3, 4}}, right: []any{[]any{1}, 4}, want: correctOrder},
-		{left: []any{9}, right: []any{[]any{8, 7, 6}}, want: wrongOrder},
-		{left: []any{[]any{4, 4}, 4, 4}, right: []any{[]any{4, 4}, 4, 4, 4}, want: correctOrder},
-		{left: []any{7, 7, 7, 7}, right: []any{7, 7, 7}, want: wrongOrder},
-		{left: []any{[]any{[]any{}}}, right: []any{[]any{}}, want: wrongOrder},
-		{left: []any{1, []any{2, []any{3, []any{4, []any{5, 6, 7}}}}, 8, 9}, right: []any{1, []any{2, []any{3, []any{4, []any{5, 6, 0}}}}, 8, 9}, want: wrongOrder},
+var input = []byte(`[1,1,3,1,1]
+[1,1,5,1,1]
+
+[[1],[2,3,4]]
+[[1],4]
+
+[9]
+[[8,7,6]]
+
+[[4,4],4,4]
+[[4,4],4,4,4]
+
+[7,7,7,7]
+[7,7,7]
+
+[]
+[3]
+
+[[[]]]
+[[]]
+
+[1,[2,[3,[4,[5,6,7]]]],8,9]
+[1,[2,[3,[4,[5,6,0]]]],8,9]
+`)
+
+func TestPart1(t *testing.T) {
+	packets, err := parseInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 13
+	if got := part1(packets); got != want {
+		t.Errorf("part1() = %d, want %d", got, want)
 	}
+}
+
+func TestPart2(t *testing.T) {
+	packets, err := parseInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 140
+	if got := part2(packets); got != want {
+		t.Errorf("part2() = %d, want %d", got, want)
+	}
+}
 
-	for _, tc := range tests {
-		t.Run("TODO", func(t *testing.T) {
-			got := compare(tc.left, tc.right)
-			if got != tc.want {
-				t.Errorf("compare(%#v, %#v) = %d, want %d", tc.left, tc.right, got, tc.want)
-			}
-		})
+func TestParseInputError(t *testing.T) {
+	if _, err := parseInput([]byte("[1,2]\n[3,]\n")); err == nil {
+		t.Error("parseInput() returned no error for an invalid packet")
 	}
 }
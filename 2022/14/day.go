@@ -9,6 +9,7 @@ import (
         "strconv"
         "strings"
 
+	"github.com/maze-mapper/advent-of-code/animate"
 	"github.com/maze-mapper/advent-of-code/coordinates"
 )
 
@@ -108,6 +109,7 @@ func simulate(cave [][]rune, sourceX, sourceY int) int {
 		default:
 			cave[sandY][sandX] = 'o'
 			sandAtRest += 1
+			animate.Frame(cave)
 			sandX = sourceX
 			sandY = sourceY
 		}
@@ -6,9 +6,12 @@ import (
         "fmt"
         "io/ioutil"
         "log"
+	"slices"
 	"sort"
 	"strconv"
         "strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type valve struct {
@@ -37,6 +40,26 @@ func parseInput(data []byte) map[string]valve {
 	return valves
 }
 
+// toDOT returns the valves as a graph labelled with their flow rates, with the valves worth opening filled.
+func toDOT(valves map[string]valve) *dot.Graph {
+	g := dot.New("valves", false)
+	for name, v := range valves {
+		attrs := dot.Attrs{"label": fmt.Sprintf("%s\\nrate=%d", name, v.flowRate)}
+		if v.flowRate > 0 {
+			attrs["style"] = "filled"
+		}
+		g.Node(name, attrs)
+		for _, next := range v.leadsTo {
+			// Tunnels are usually listed from both valves so only draw them once.
+			if next < name && slices.Contains(valves[next].leadsTo, name) {
+				continue
+			}
+			g.Edge(name, next, nil)
+		}
+	}
+	return g
+}
+
 type Node struct {
 	name string
 	elephant string
@@ -388,6 +411,11 @@ func Run(inputFile string) {
                 log.Fatal(err)
         }
         valves := parseInput(data)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(valves)); err != nil {
+			log.Fatal(err)
+		}
+	}
 
         p1 := part1(valves)
         fmt.Println("Part 1:", p1)
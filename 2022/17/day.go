@@ -7,6 +7,8 @@ import (
 	"log"
 	"math"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/animate"
 )
 
 var shapeOrder = [][][]bool{
@@ -75,6 +77,33 @@ func printChamber(chamber, shape [][]bool, posX, posY, currentHeight int) {
 	fmt.Print("\n")
 }
 
+// frameRows is the number of rows at the top of the chamber shown in each animation frame
+const frameRows = 40
+
+// recordChamber records a frame of the top of the chamber with the falling shape if animation is enabled
+func recordChamber(chamber, shape [][]bool, posX, posY, currentHeight int) {
+	if !animate.Enabled() {
+		return
+	}
+	top := min(max(currentHeight, posY+len(shape)-1), len(chamber)-1)
+	bottom := max(top-frameRows+1, 0)
+	frame := make([][]rune, 0, top-bottom+1)
+	for y := top; y >= bottom; y-- {
+		row := make([]rune, len(chamber[y]))
+		for x, b := range chamber[y] {
+			row[x] = '.'
+			if b {
+				row[x] = '#'
+			}
+			if sy, sx := y-posY, x-posX; sy >= 0 && sy < len(shape) && sx >= 0 && sx < len(shape[sy]) && shape[sy][sx] {
+				row[x] = '@'
+			}
+		}
+		frame = append(frame, row)
+	}
+	animate.Frame(frame)
+}
+
 func canMove(chamber, shape [][]bool, posX, posY, dirX, dirY int) bool {
 	// Check if floor and wall block movement.
 	if dirX < 0 && posX == 0 || dirX > 0 && posX+len(shape[0]) == len(chamber[0]) || dirY < 0 && posY == 0 {
@@ -146,6 +175,7 @@ func tetris(jets string, numShapes int, maxHeight int) int {
 			if falling {
 				posY -= 1
 			}
+			recordChamber(chamber, shape, posX, posY, currentHeight)
 		}
 
 		for y := range shape {
@@ -7,6 +7,8 @@ import (
 	"log"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type monkey struct {
@@ -54,6 +56,27 @@ func (m *monkey) pathToMonkey(s string) ([]string, bool) {
 	return currentPath, false
 }
 
+// toDOT returns the tree of monkeys as a graph from each monkey to the monkeys it waits for, with the human
+// highlighted.
+func toDOT(root *monkey) *dot.Graph {
+	g := dot.New("monkeys", true)
+	var add func(m *monkey)
+	add = func(m *monkey) {
+		if m.left == nil || m.right == nil {
+			g.Node(m.label, dot.Attrs{"label": fmt.Sprintf("%s\\n%d", m.label, m.number), "shape": "box"})
+			return
+		}
+		g.Node(m.label, dot.Attrs{"label": fmt.Sprintf("%s\\n%s", m.label, m.op)})
+		g.Edge(m.label, m.left.label, dot.Attrs{"label": "L"})
+		g.Edge(m.label, m.right.label, dot.Attrs{"label": "R"})
+		add(m.left)
+		add(m.right)
+	}
+	add(root)
+	g.Highlight("humn")
+	return g
+}
+
 func parseInput(data []byte) *monkey {
 	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
 	monkeys := make(map[string]*monkey, len(lines))
@@ -158,6 +181,11 @@ func Run(inputFile string) {
 		log.Fatal(err)
 	}
 	rootMonkey := parseInput(data)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(rootMonkey)); err != nil {
+			log.Fatal(err)
+		}
+	}
 	fmt.Println(rootMonkey)
 
 	p1 := part1(rootMonkey)
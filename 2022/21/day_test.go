@@ -0,0 +1,36 @@
+package day21
+
+import (
+	"reflect"
+	"testing"
+)
+
+var input = []byte(`root: pppw + sjmn
+dbpl: 5
+cczh: sllz + lgvd
+zczc: 2
+ptdq: humn - dvpt
+dvpt: 3
+lfqf: 4
+humn: 5
+ljgn: 2
+sjmn: drzm * dbpl
+sllz: 4
+pppw: cczh / lfqf
+lgvd: ljgn * ptdq
+drzm: hmdt - zczc
+hmdt: 32`)
+
+func TestToDOT(t *testing.T) {
+	g := toDOT(parseInput(input))
+	if got := len(g.Nodes()); got != 15 {
+		t.Errorf("toDOT() has %d nodes, want 15", got)
+	}
+	// Each of the 7 monkeys doing an operation waits for 2 others
+	if got := g.Edges(); got != 14 {
+		t.Errorf("toDOT() has %d edges, want 14", got)
+	}
+	if got, want := g.HighlightedNodes(), []string{"humn"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("toDOT() highlights %v, want %v", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -123,186 +124,154 @@ func wrap(x, y, width, height int) (int, int) {
 	return x, y
 }
 
-func customWrap(x, y, facing int) (int, int, int) {
-	xDir, yDir := moveDirection(facing)
-	x += xDir
-	y += yDir
-
-	xOffset := x % 50
-	yOffset := y % 50
-
-	switch {
-	// blue
-	case y == -1 && x >= 50 && x < 100 && facing == facingUp:
-		facing = facingRight
-		x = 0
-		y = 150 + xOffset
-
-		//black
-	case y == -1 && x >= 100 && x < 150 && facing == facingUp:
-		facing = facingUp
-		y = 199
-		x = 0 + xOffset
-
-	//red
-	case y == 50 && x >= 100 && x < 150 && facing == facingDown:
-		facing = facingLeft
-		x = 99
-		y = 50 + xOffset
-
-		// red
-	case y == 99 && x >= 0 && x < 50 && facing == facingUp:
-		facing = facingRight
-		x = 50
-		y = 50 + xOffset
-
-		//red
-	case y == 150 && x >= 50 && x < 100 && facing == facingDown:
-		facing = facingLeft
-		x = 49
-		y = 150 + xOffset
-
-		//black
-	case y == 200 && x >= 0 && x < 50 && facing == facingDown:
-		facing = facingDown
-		y = 0
-		x = 100 + xOffset
-
-		// blue
-	case x == -1 && y >= 150 && y < 200 && facing == facingLeft:
-		facing = facingDown
-		y = 0
-		x = 50 + yOffset
-
-		//red
-	case x == 50 && y >= 150 && y < 200 && facing == facingRight:
-		facing = facingUp
-		y = 149
-		x = 50 + yOffset
-
-		//green dots
-	case x == -1 && y >= 100 && y < 150 && facing == facingLeft:
-		facing = facingRight
-		x = 50
-		y = 49 - yOffset
-
-		//green
-	case x == 100 && y >= 100 && y < 150 && facing == facingRight:
-		facing = facingLeft
-		x = 149
-		y = 49 - yOffset
-
-		//red
-	case x == 49 && y >= 50 && y < 100 && facing == facingLeft:
-		facing = facingDown
-		y = 100
-		x = 0 + yOffset
-
-		//red
-	case x == 100 && y >= 50 && y < 100 && facing == facingRight:
-		facing = facingUp
-		y = 49
-		x = 100 + yOffset
-
-	//green dots
-	case x == 49 && y >= 0 && y < 50 && facing == facingLeft:
-		facing = facingRight
-		x = 0
-		y = 149 - yOffset
-
-		//green
-	case x == 150 && y >= 0 && y < 50 && facing == facingRight:
-		facing = facingLeft
-		x = 99
-		y = 149 - yOffset
-
-	}
+// vec is a vector in three dimensions.
+type vec [3]int
 
-	return x, y, facing
+func (v vec) neg() vec {
+	return vec{-v[0], -v[1], -v[2]}
 }
 
-func cubeWrap(board [][]string, x, y, facing, size int) (int, int, int) {
-	xDir, yDir := moveDirection(facing)
-	nextXPos := x + xDir
-	nextYPos := y + yDir
-
-	// Determine if the new position is on another cube face.
-	currentXTile := x / size
-	currentYTile := y / size
-	nextXTile := nextXPos / size
-	nextYTile := nextYPos / size
+// face is a face of the cube, located at a tile of the board.
+// The vectors right, down and normal give the directions in three dimensions of moving right and down on the board and
+// out of the cube once the board is folded.
+type face struct {
+	tileX, tileY        int
+	right, down, normal vec
+}
 
-	if currentXTile == nextXTile && currentYTile == nextYTile {
-		return nextXPos, nextYPos, facing
+// edge returns the outward direction of the edge of the face crossed when moving with the given facing.
+func (f *face) edge(facing int) vec {
+	switch facing {
+	case facingRight:
+		return f.right
+	case facingDown:
+		return f.down
+	case facingLeft:
+		return f.right.neg()
+	default:
+		return f.down.neg()
 	}
+}
 
-	// Moving in the x direction.
-	if nextXTile != currentXTile {
-		fmt.Println("Change tile x")
-		// TODO x > len
+// cube is the board folded in to a cube.
+type cube struct {
+	size     int
+	faces    map[[2]int]*face
+	byNormal map[vec]*face
+}
 
-		// Next space is on an adjacent face.
-		if board[nextYPos][nextXPos] != " " {
-			return nextXPos, nextYPos, facing
+// foldCube detects the faces of the cube on the board and folds them together.
+func foldCube(board [][]string) (*cube, error) {
+	cells := 0
+	for _, row := range board {
+		for _, b := range row {
+			if b != " " {
+				cells++
+			}
 		}
+	}
+	size := int(math.Sqrt(float64(cells / 6)))
+	if size == 0 || 6*size*size != cells {
+		return nil, fmt.Errorf("%d cells cannot form the faces of a cube", cells)
+	}
 
-		yOffset := nextYPos % size
-
-		// Check if there is a cube face downwards one tile.
-		yDown := nextYPos - yOffset - 1
-		xDown := x + (yOffset+1)*xDir
-		if board[yDown][xDown] != " " {
-			nextXPos = xDown
-			nextYPos = yDown
-			facing = changeDirection(facing, turnRight)
-			return nextXPos, nextYPos, facing
+	c := &cube{size: size, faces: map[[2]int]*face{}, byNormal: map[vec]*face{}}
+	for ty := 0; ty*size < len(board); ty++ {
+		for tx := 0; tx*size < len(board[ty*size]); tx++ {
+			if board[ty*size][tx*size] != " " {
+				c.faces[[2]int{tx, ty}] = &face{tileX: tx, tileY: ty}
+			}
 		}
+	}
+	if len(c.faces) != 6 {
+		return nil, fmt.Errorf("found %d faces of size %d, expected 6", len(c.faces), size)
+	}
 
-		// Check if there is a cube face upwards one tile.
-		yUp := nextYPos + size - yOffset
-		xUp := x + (size-yOffset)*xDir
-		if board[yUp][xUp] != " " {
-			nextXPos = xUp
-			nextYPos = yUp
-			facing = changeDirection(facing, turnLeft)
-			return nextXPos, nextYPos, facing
+	// Fold outwards from the first face, rotating the vectors about each shared edge.
+	start := &face{right: vec{1, 0, 0}, down: vec{0, 1, 0}, normal: vec{0, 0, -1}}
+	for tx := 0; ; tx++ {
+		if f, ok := c.faces[[2]int{tx, 0}]; ok {
+			start.tileX = f.tileX
+			c.faces[[2]int{tx, 0}] = start
+			break
 		}
 	}
-
-	// Moving in the y direction.
-	if nextYTile != currentYTile {
-		fmt.Println("Change tile y")
-		// TODO check bounds
-
-		// Next space is on an adjacent face.
-		if board[nextYPos][nextXPos] != " " {
-			return nextXPos, nextYPos, facing
+	queue := []*face{start}
+	seen := map[*face]bool{start: true}
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		c.byNormal[f.normal] = f
+		neighbours := []struct {
+			dx, dy              int
+			right, down, normal vec
+		}{
+			{dx: 1, right: f.normal.neg(), down: f.down, normal: f.right},
+			{dx: -1, right: f.normal, down: f.down, normal: f.right.neg()},
+			{dy: 1, right: f.right, down: f.normal.neg(), normal: f.down},
+			{dy: -1, right: f.right, down: f.normal, normal: f.down.neg()},
+		}
+		for _, n := range neighbours {
+			g, ok := c.faces[[2]int{f.tileX + n.dx, f.tileY + n.dy}]
+			if !ok || seen[g] {
+				continue
+			}
+			g.right, g.down, g.normal = n.right, n.down, n.normal
+			seen[g] = true
+			queue = append(queue, g)
 		}
+	}
+	if len(c.byNormal) != 6 {
+		return nil, fmt.Errorf("faces do not fold in to a cube")
+	}
+	return c, nil
+}
 
-		xOffset := nextXPos % size
+// wrap returns the position and facing after one step, moving over the edges of the cube.
+func (c *cube) wrap(x, y, facing int) (int, int, int) {
+	xDir, yDir := moveDirection(facing)
+	nextX, nextY := x+xDir, y+yDir
+	if nextX >= 0 && nextY >= 0 && nextX/c.size == x/c.size && nextY/c.size == y/c.size {
+		return nextX, nextY, facing
+	}
 
-		// Check if there is a cube face left one tile.
-		yLeft := y + (xOffset+1)*yDir
-		xLeft := nextXPos - xOffset - 1
-		if board[yLeft][xLeft] != " " {
-			nextXPos = xLeft
-			nextYPos = yLeft
-			facing = changeDirection(facing, turnLeft)
-			return nextXPos, nextYPos, facing
-		}
+	f := c.faces[[2]int{x / c.size, y / c.size}]
+	g := c.byNormal[f.edge(facing)]
 
-		// Check if there is a cube face right one tile.
-		yRight := y + (size-xOffset)*yDir
-		xRight := nextXPos - xOffset - 1
-		if board[yRight][xRight] != " " {
-			nextXPos = xRight
-			nextYPos = yRight
-			facing = changeDirection(facing, turnRight)
-			return nextXPos, nextYPos, facing
-		}
+	// Offsets along edges run clockwise around each face, so run in opposite directions along a shared edge.
+	last := c.size - 1
+	var along int
+	switch facing {
+	case facingRight:
+		along = y % c.size
+	case facingDown:
+		along = last - x%c.size
+	case facingLeft:
+		along = last - y%c.size
+	case facingUp:
+		along = x % c.size
 	}
+	along = last - along
 
-	return nextXPos, nextYPos, facing
+	// Enter the next face through the edge it shares with this face.
+	var entry int
+	for e := 0; e < facingMax; e++ {
+		if g.edge(e) == f.normal {
+			entry = e
+		}
+	}
+	var ox, oy int
+	switch entry {
+	case facingRight:
+		ox, oy = last, along
+	case facingDown:
+		ox, oy = last-along, last
+	case facingLeft:
+		ox, oy = 0, last-along
+	case facingUp:
+		ox, oy = along, 0
+	}
+	return g.tileX*c.size + ox, g.tileY*c.size + oy, (entry + 2) % facingMax
 }
 
 func score(row, col, facing int) int {
@@ -347,8 +316,11 @@ func part1(board [][]string, path []int) int {
 	return score(yPos, xPos, facing)
 }
 
-func part2(board [][]string, path []int) int {
-	//	cubeSize := 50
+func part2(board [][]string, path []int) (int, error) {
+	c, err := foldCube(board)
+	if err != nil {
+		return 0, err
+	}
 	xPos, yPos := startingPosition(board)
 	facing := facingRight
 
@@ -357,30 +329,22 @@ func part2(board [][]string, path []int) int {
 
 	for _, p := range path {
 		if isMove {
-			fmt.Printf("Move %d, facing %d from (%d,%d)\n", p, facing, xPos, yPos)
 			for step := 0; step < p; step++ {
-				//nextXPos, nextYPos, nextFacing := cubeWrap(board, xPos, yPos, facing, 50)
-				nextXPos, nextYPos, nextFacing := customWrap(xPos, yPos, facing)
-
+				nextXPos, nextYPos, nextFacing := c.wrap(xPos, yPos, facing)
 				if board[nextYPos][nextXPos] == "#" {
 					break
-				} else if board[nextYPos][nextXPos] == " " {
-					log.Fatal("not a valid space")
-				} else {
-					xPos = nextXPos
-					yPos = nextYPos
-					facing = nextFacing
 				}
-				fmt.Println(xPos, yPos, facing)
+				xPos = nextXPos
+				yPos = nextYPos
+				facing = nextFacing
 			}
 		} else {
 			facing = changeDirection(facing, p)
-			fmt.Printf("Turn %d, now facing %d\n", p, facing)
 		}
 		isMove = !isMove
 	}
 
-	return score(yPos, xPos, facing)
+	return score(yPos, xPos, facing), nil
 }
 
 func Run(inputFile string) {
@@ -389,14 +353,13 @@ func Run(inputFile string) {
 		log.Fatal(err)
 	}
 	board, path := parseInput(data)
-	fmt.Println(path)
-	for _, row := range board {
-		fmt.Println(row)
-	}
 
 	p1 := part1(board, path)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(board, path)
+	p2, err := part2(board, path)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 2:", p2)
 }
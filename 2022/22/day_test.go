@@ -0,0 +1,119 @@
+package day22
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var input = []byte(`        ...#
+        .#..
+        #...
+        ....
+...#.......#
+........#...
+..#....#....
+..........#.
+        ...#....
+        .....#..
+        .#......
+        ......#.
+
+10R5L5R10L4R5L5`)
+
+func TestPart1(t *testing.T) {
+	want := 6032
+	board, path := parseInput(input)
+	got := part1(board, path)
+	if got != want {
+		t.Errorf("part1(%s) = %d, want %d", input, got, want)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	want := 5031
+	board, path := parseInput(input)
+	got, err := part2(board, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("part2(%s) = %d, want %d", input, got, want)
+	}
+}
+
+// nets are the eleven nets of a cube, with # marking the faces.
+var nets = [][]string{
+	{"#...", "####", "#..."},
+	{"#...", "####", ".#.."},
+	{"#...", "####", "..#."},
+	{"#...", "####", "...#"},
+	{".#..", "####", ".#.."},
+	{".#..", "####", "..#."},
+	{"##..", ".###", ".#.."},
+	{"##..", ".###", "..#."},
+	{"##..", ".###", "...#"},
+	{"##..", ".##.", "..##"},
+	{"###..", "..###"},
+}
+
+// makeBoard returns an open board with faces of the given size laid out as in the net.
+func makeBoard(net []string, size int) [][]string {
+	var board [][]string
+	for _, row := range net {
+		var line strings.Builder
+		for _, tile := range row {
+			if tile == '#' {
+				line.WriteString(strings.Repeat(".", size))
+			} else {
+				line.WriteString(strings.Repeat(" ", size))
+			}
+		}
+		for i := 0; i < size; i++ {
+			board = append(board, strings.Split(line.String(), ""))
+		}
+	}
+	return board
+}
+
+func TestCubeWrap(t *testing.T) {
+	size := 3
+	for _, net := range nets {
+		t.Run(strings.Join(net, "_"), func(t *testing.T) {
+			board := makeBoard(net, size)
+			c, err := foldCube(board)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for y, row := range board {
+				for x, b := range row {
+					if b == " " {
+						continue
+					}
+					for facing := 0; facing < facingMax; facing++ {
+						start := fmt.Sprint(x, y, facing)
+
+						// Walking around the cube returns to the start.
+						px, py, pf := x, y, facing
+						for step := 0; step < 4*size; step++ {
+							px, py, pf = c.wrap(px, py, pf)
+							if board[py][px] == " " {
+								t.Fatalf("from %s stepped off the cube to %d,%d", start, px, py)
+							}
+						}
+						if got := fmt.Sprint(px, py, pf); got != start {
+							t.Errorf("from %s walked around the cube to %s", start, got)
+						}
+
+						// Stepping forwards then backwards returns to the start.
+						px, py, pf = c.wrap(x, y, facing)
+						px, py, pf = c.wrap(px, py, (pf+2)%facingMax)
+						if got := fmt.Sprint(px, py, (pf+2)%facingMax); got != start {
+							t.Errorf("from %s stepped back to %s", start, got)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/maze-mapper/advent-of-code/animate"
 	"github.com/maze-mapper/advent-of-code/coordinates"
 )
 
@@ -117,6 +118,9 @@ func round(elves map[coordinates.Coord]struct{}, dir int) bool {
 			moved = true
 		}
 	}
+	if animate.Enabled() {
+		animate.Frame(animate.Points(elves, '#'))
+	}
 	return moved
 }
 
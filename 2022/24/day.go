@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 func parseInput(data []byte) (coordinates.Coord, coordinates.Coord, map[coordinates.Coord][]rune, map[coordinates.Coord]struct{}, int, int) {
@@ -69,14 +71,32 @@ func (node *Node) state(maxX, maxY int, walls map[coordinates.Coord]struct{}) st
 	return b.String()
 }
 
+// step is a position of the elves on a path, linked to the position in the minute before.
+// Paths are kept apart from nodes so that they do not keep the blizzards of earlier minutes alive.
+type step struct {
+	elves coordinates.Coord
+	prev  *step
+}
+
 type Node struct {
 	minute    int
 	elves     coordinates.Coord
 	blizzards map[coordinates.Coord][]rune
+	trail     *step
 	priority  int
 	index     int
 }
 
+// path returns the positions of the elves in each minute up to the node.
+func (node *Node) path() []coordinates.Coord {
+	path := []coordinates.Coord{}
+	for s := node.trail; s != nil; s = s.prev {
+		path = append(path, s.elves)
+	}
+	slices.Reverse(path)
+	return path
+}
+
 // A PriorityQueue implements heap.Interface and holds Nodes.
 type PriorityQueue []*Node
 
@@ -178,6 +198,7 @@ func aStar(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune,
 		minute:    0,
 		elves:     start,
 		blizzards: blizzards,
+		trail:     &step{elves: start},
 	}
 	pq[0] = startNode
 	heap.Init(&pq)
@@ -202,6 +223,7 @@ func aStar(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune,
 				minute:    newMinute,
 				elves:     move,
 				blizzards: newBlizzards,
+				trail:     &step{elves: move, prev: node.trail},
 				priority:  newMinute + coordinates.ManhattanDistance(move, end),
 			}
 			s := newNode.state(maxX, maxY, walls)
@@ -215,20 +237,42 @@ func aStar(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune,
 	return &Node{}
 }
 
-func part1(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune, walls map[coordinates.Coord]struct{}, maxX, maxY int) int {
+func part1(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune, walls map[coordinates.Coord]struct{}, maxX, maxY int) (int, []coordinates.Coord) {
 	node := aStar(start, end, blizzards, walls, maxX, maxY)
-	return node.minute
+	return node.minute, node.path()
 }
 
-func part2(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune, walls map[coordinates.Coord]struct{}, maxX, maxY int) int {
+func part2(start, end coordinates.Coord, blizzards map[coordinates.Coord][]rune, walls map[coordinates.Coord]struct{}, maxX, maxY int) (int, []coordinates.Coord) {
 	minutes := 0
 	node := aStar(start, end, blizzards, walls, maxX, maxY)
 	minutes += node.minute
+	path := node.path()
 	node = aStar(end, start, node.blizzards, walls, maxX, maxY)
 	minutes += node.minute
+	path = append(path, node.path()[1:]...)
 	node = aStar(start, end, node.blizzards, walls, maxX, maxY)
 	minutes += node.minute
-	return minutes
+	path = append(path, node.path()[1:]...)
+	return minutes, path
+}
+
+// drawPath draws the path of the elves over the walls of the valley.
+// Blizzards are not drawn as they move every minute.
+func drawPath(label string, walls map[coordinates.Coord]struct{}, maxX, maxY int, path []coordinates.Coord) {
+	grid := make([][]rune, maxY+1)
+	for y := range grid {
+		grid[y] = make([]rune, maxX+1)
+		for x := range grid[y] {
+			if _, ok := walls[coordinates.Coord{X: x, Y: y}]; ok {
+				grid[y][x] = '#'
+			} else {
+				grid[y][x] = '.'
+			}
+		}
+	}
+	if err := overlay.Draw(label, grid, overlay.Arrows(path)); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func Run(inputFile string) {
@@ -238,9 +282,14 @@ func Run(inputFile string) {
 	}
 	start, end, blizzards, walls, maxX, maxY := parseInput(data)
 
-	p1 := part1(start, end, blizzards, walls, maxX, maxY)
+	p1, path1 := part1(start, end, blizzards, walls, maxX, maxY)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(start, end, blizzards, walls, maxX, maxY)
+	p2, path2 := part2(start, end, blizzards, walls, maxX, maxY)
 	fmt.Println("Part 2:", p2)
+
+	if overlay.Enabled() {
+		drawPath("part1", walls, maxX, maxY, path1)
+		drawPath("part2", walls, maxX, maxY, path2)
+	}
 }
@@ -0,0 +1,249 @@
+// Distress signal packets
+package packet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Packet is either an integer or a list of packets
+type Packet struct {
+	// Int is the value of an integer packet
+	Int int
+	// List holds the elements of a list packet
+	List []Packet
+	// IsList is whether the packet is a list, which may be empty
+	IsList bool
+}
+
+// Int returns an integer packet
+func Int(v int) Packet {
+	return Packet{Int: v}
+}
+
+// List returns a list packet of the elements
+func List(elems ...Packet) Packet {
+	return Packet{List: elems, IsList: true}
+}
+
+// SyntaxError is returned when a packet cannot be parsed, giving the offset of the problem
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Msg)
+}
+
+// Parse returns the packet written in s, such as "[1,[2,3],[]]".
+// Packets are written without spaces and integers without leading zeros, so that String returns s exactly.
+func Parse(s string) (Packet, error) {
+	p := &parser{s: s}
+	packet, err := p.packet()
+	if err != nil {
+		return Packet{}, err
+	}
+	if p.pos != len(s) {
+		return Packet{}, &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf("unexpected %q after packet", s[p.pos])}
+	}
+	return packet, nil
+}
+
+// MustParse is like Parse but panics if the packet cannot be parsed, for packets known when the program is written
+func MustParse(s string) Packet {
+	p, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// parser is a recursive descent parser over a packet string
+type parser struct {
+	s   string
+	pos int
+}
+
+// errorf returns a syntax error at the current position
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// packet parses an integer or list starting at the current position
+func (p *parser) packet() (Packet, error) {
+	if p.pos >= len(p.s) {
+		return Packet{}, p.errorf("unexpected end of packet")
+	}
+	if p.s[p.pos] == '[' {
+		return p.list()
+	}
+	return p.integer()
+}
+
+// list parses a list starting at the current position
+func (p *parser) list() (Packet, error) {
+	p.pos++ // Opening bracket.
+	list := List()
+	if p.pos < len(p.s) && p.s[p.pos] == ']' {
+		p.pos++
+		return list, nil
+	}
+	for {
+		elem, err := p.packet()
+		if err != nil {
+			return Packet{}, err
+		}
+		list.List = append(list.List, elem)
+		if p.pos >= len(p.s) {
+			return Packet{}, p.errorf("unexpected end of packet, want ',' or ']'")
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return list, nil
+		default:
+			return Packet{}, p.errorf("unexpected %q, want ',' or ']'", p.s[p.pos])
+		}
+	}
+}
+
+// integer parses an integer starting at the current position
+func (p *parser) integer() (Packet, error) {
+	start := p.pos
+	if p.s[p.pos] == '-' {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == digits {
+		return Packet{}, p.errorf("unexpected %s, want an integer or '['", p.next())
+	}
+	if p.s[digits] == '0' && p.pos-digits > 1 {
+		return Packet{}, &SyntaxError{Offset: digits, Msg: "integer has a leading zero"}
+	}
+	v, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		return Packet{}, &SyntaxError{Offset: start, Msg: err.Error()}
+	}
+	if v == 0 && start != digits {
+		return Packet{}, &SyntaxError{Offset: start, Msg: "negative zero"}
+	}
+	return Int(v), nil
+}
+
+// next describes the character at the current position for error messages
+func (p *parser) next() string {
+	if p.pos >= len(p.s) {
+		return "end of packet"
+	}
+	return strconv.QuoteRune(rune(p.s[p.pos]))
+}
+
+// String returns the packet as written in the distress signal
+func (p Packet) String() string {
+	var sb strings.Builder
+	p.write(&sb)
+	return sb.String()
+}
+
+// write writes the packet to a string builder
+func (p Packet) write(sb *strings.Builder) {
+	if !p.IsList {
+		sb.WriteString(strconv.Itoa(p.Int))
+		return
+	}
+	sb.WriteByte('[')
+	for i, elem := range p.List {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		elem.write(sb)
+	}
+	sb.WriteByte(']')
+}
+
+// MarshalJSON returns the packet as a JSON array or number, which is how it is written in the distress signal
+func (p Packet) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON sets the packet from a JSON array or number.
+// Arrays may only hold arrays and integers.
+func (p *Packet) UnmarshalJSON(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+	packet, err := fromJSON(v)
+	if err != nil {
+		return err
+	}
+	*p = packet
+	return nil
+}
+
+// fromJSON returns the packet of a decoded JSON value
+func fromJSON(v any) (Packet, error) {
+	switch v := v.(type) {
+	case json.Number:
+		n, err := strconv.Atoi(v.String())
+		if err != nil {
+			return Packet{}, fmt.Errorf("packet integer %s: %w", v, err)
+		}
+		return Int(n), nil
+	case []any:
+		list := List()
+		for _, elem := range v {
+			p, err := fromJSON(elem)
+			if err != nil {
+				return Packet{}, err
+			}
+			list.List = append(list.List, p)
+		}
+		return list, nil
+	}
+	return Packet{}, fmt.Errorf("packet cannot hold JSON value %v", v)
+}
+
+// Compare returns -1 if a is in the right order before b, 1 if it is in the wrong order and 0 if the order is not
+// decided, so that packets may be sorted with slices.SortFunc.
+// Integers are compared by value and lists element by element, with the shorter list first if one runs out.
+// When an integer is compared with a list it is compared as a list holding only that integer.
+func Compare(a, b Packet) int {
+	switch {
+	case !a.IsList && !b.IsList:
+		switch {
+		case a.Int < b.Int:
+			return -1
+		case a.Int > b.Int:
+			return 1
+		}
+		return 0
+	case !a.IsList:
+		return Compare(List(a), b)
+	case !b.IsList:
+		return Compare(a, List(b))
+	}
+	for i := 0; i < len(a.List) && i < len(b.List); i++ {
+		if c := Compare(a.List[i], b.List[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a.List) < len(b.List):
+		return -1
+	case len(a.List) > len(b.List):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package packet
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		left, right string
+		want        int
+	}{
+		{left: "[1,1,3,1,1]", right: "[1,1,5,1,1]", want: -1},
+		{left: "[[1],[2,3,4]]", right: "[[1],4]", want: -1},
+		{left: "[9]", right: "[[8,7,6]]", want: 1},
+		{left: "[[4,4],4,4]", right: "[[4,4],4,4,4]", want: -1},
+		{left: "[7,7,7,7]", right: "[7,7,7]", want: 1},
+		{left: "[]", right: "[3]", want: -1},
+		{left: "[[[]]]", right: "[[]]", want: 1},
+		{left: "[1,[2,[3,[4,[5,6,7]]]],8,9]", right: "[1,[2,[3,[4,[5,6,0]]]],8,9]", want: 1},
+		{left: "[[1]]", right: "[1]", want: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.left+" "+tc.right, func(t *testing.T) {
+			got := Compare(MustParse(tc.left), MustParse(tc.right))
+			if got != tc.want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", tc.left, tc.right, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	want := List(Int(1), List(Int(2), Int(-3)), List(), Int(10))
+	got, err := Parse("[1,[2,-3],[],10]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int
+	}{
+		{input: "", offset: 0},
+		{input: "[1,2", offset: 4},
+		{input: "[1,,2]", offset: 3},
+		{input: "[1 2]", offset: 2},
+		{input: "[1]]", offset: 3},
+		{input: "[01]", offset: 1},
+		{input: "[a]", offset: 1},
+		{input: "[-]", offset: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a SyntaxError", tc.input, err)
+			}
+			if syntaxErr.Offset != tc.offset {
+				t.Errorf("Parse(%q) error at offset %d, want %d: %v", tc.input, syntaxErr.Offset, tc.offset, err)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var packets []Packet
+	if err := json.Unmarshal([]byte(`[[1, [2, 3]], 4, []]`), &packets); err != nil {
+		t.Fatal(err)
+	}
+	want := []Packet{MustParse("[1,[2,3]]"), Int(4), List()}
+	if !reflect.DeepEqual(packets, want) {
+		t.Errorf("json.Unmarshal() = %v, want %v", packets, want)
+	}
+
+	data, err := json.Marshal(packets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `[[1,[2,3]],4,[]]` {
+		t.Errorf("json.Marshal() = %s, want [[1,[2,3]],4,[]]", got)
+	}
+
+	var p Packet
+	if err := json.Unmarshal([]byte(`[1, "two"]`), &p); err == nil {
+		t.Errorf("json.Unmarshal() of a string returned no error")
+	}
+}
+
+// sign returns -1, 0 or 1 for negative, zero or positive n
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+var seeds = []string{"[1,1,3,1,1]", "[[1],[2,3,4]]", "[[1],4]", "[9]", "[[8,7,6]]", "[]", "[[]]", "[[[]]]", "[[2]]", "[6]"}
+
+// FuzzParse checks that packets which parse are written back exactly and survive a trip through JSON
+func FuzzParse(f *testing.F) {
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		p, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if got := p.String(); got != s {
+			t.Errorf("Parse(%q).String() = %q", s, got)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q Packet
+		if err := json.Unmarshal(data, &q); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+		}
+		if !reflect.DeepEqual(p, q) {
+			t.Errorf("packet %s is %s after JSON", p, q)
+		}
+	})
+}
+
+// FuzzCompare checks that Compare orders packets consistently: reflexive, antisymmetric and transitive
+func FuzzCompare(f *testing.F) {
+	for i := range seeds {
+		f.Add(seeds[i], seeds[(i+1)%len(seeds)], seeds[(i+2)%len(seeds)])
+	}
+	f.Fuzz(func(t *testing.T, x, y, z string) {
+		a, errA := Parse(x)
+		b, errB := Parse(y)
+		c, errC := Parse(z)
+		if errA != nil || errB != nil || errC != nil {
+			return
+		}
+		if got := Compare(a, a); got != 0 {
+			t.Errorf("Compare(%s, %s) = %d, want 0", a, a, got)
+		}
+		if ab, ba := Compare(a, b), Compare(b, a); sign(ab) != -sign(ba) {
+			t.Errorf("Compare(%s, %s) = %d but Compare(%s, %s) = %d", a, b, ab, b, a, ba)
+		}
+		if Compare(a, b) <= 0 && Compare(b, c) <= 0 && Compare(a, c) > 0 {
+			t.Errorf("%s <= %s <= %s but Compare(%s, %s) = %d", a, b, c, a, c, Compare(a, c))
+		}
+	})
+}
@@ -0,0 +1,344 @@
+// Virtual filesystem rebuilt from a terminal transcript
+package vfs
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Dir is a directory in the filesystem
+type Dir struct {
+	Name   string
+	Parent *Dir
+	// Dirs are the sub-directories by name
+	Dirs map[string]*Dir
+	// Files are the sizes of the files by name
+	Files map[string]int
+	// listed is whether the contents of the directory have been seen with ls
+	listed bool
+}
+
+// newDir returns an empty directory
+func newDir(name string, parent *Dir) *Dir {
+	return &Dir{
+		Name:   name,
+		Parent: parent,
+		Dirs:   map[string]*Dir{},
+		Files:  map[string]int{},
+	}
+}
+
+// Path returns the absolute path of the directory, such as "/a/e"
+func (d *Dir) Path() string {
+	if d.Parent == nil {
+		return "/"
+	}
+	if d.Parent.Parent == nil {
+		return "/" + d.Name
+	}
+	return d.Parent.Path() + "/" + d.Name
+}
+
+// Size returns the total size of the files in the directory and all of its sub-directories
+func (d *Dir) Size() int {
+	size := 0
+	for _, s := range d.Files {
+		size += s
+	}
+	for _, sub := range d.Dirs {
+		size += sub.Size()
+	}
+	return size
+}
+
+// Walk calls f for the directory and then each of its sub-directories in name order
+func (d *Dir) Walk(f func(*Dir)) {
+	f(d)
+	var names []string
+	for name := range d.Dirs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		d.Dirs[name].Walk(f)
+	}
+}
+
+// FS is a filesystem with a current directory
+type FS struct {
+	Root *Dir
+	Cwd  *Dir
+}
+
+// New returns an empty filesystem in the root directory
+func New() *FS {
+	root := newDir("", nil)
+	return &FS{Root: root, Cwd: root}
+}
+
+// TranscriptError is returned when a transcript cannot be replayed, giving the line number of the problem
+type TranscriptError struct {
+	Line int
+	Msg  string
+}
+
+func (e *TranscriptError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Replay returns the filesystem explored by the cd and ls commands of a transcript.
+// The transcript starts in the root directory. A directory listed more than once must be listed the same each time.
+func Replay(transcript string) (*FS, error) {
+	fs := New()
+	lines := strings.Split(strings.TrimSuffix(transcript, "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "$ cd "):
+			if err := fs.Cd(strings.TrimPrefix(line, "$ cd ")); err != nil {
+				return nil, &TranscriptError{Line: i + 1, Msg: err.Error()}
+			}
+		case line == "$ ls":
+			start := i + 1
+			for i+1 < len(lines) && !strings.HasPrefix(lines[i+1], "$") {
+				i++
+			}
+			if err := fs.list(lines[start : i+1]); err != nil {
+				// Line 0 of the output is the ls command
+				err.Line += start
+				return nil, err
+			}
+		default:
+			return nil, &TranscriptError{Line: i + 1, Msg: fmt.Sprintf("unexpected %q, want a cd or ls command", line)}
+		}
+	}
+	return fs, nil
+}
+
+// Cd changes the current directory to an absolute or relative path.
+// The directories on the path must already be known from a listing.
+func (fs *FS) Cd(path string) error {
+	d := fs.Cwd
+	if strings.HasPrefix(path, "/") {
+		d = fs.Root
+	}
+	for _, name := range strings.Split(path, "/") {
+		switch name {
+		case "", ".":
+		case "..":
+			// As in a shell, the parent of the root is the root
+			if d.Parent != nil {
+				d = d.Parent
+			}
+		default:
+			sub, ok := d.Dirs[name]
+			if !ok {
+				return fmt.Errorf("cd %s: no directory %s in %s", path, name, d.Path())
+			}
+			d = sub
+		}
+	}
+	fs.Cwd = d
+	return nil
+}
+
+// list records the output of ls in the current directory.
+// Errors give the line of the output counting from 1, or 0 when the whole listing is wrong.
+func (fs *FS) list(output []string) *TranscriptError {
+	dirs := map[string]bool{}
+	files := map[string]int{}
+	for i, line := range output {
+		errorf := func(format string, args ...any) *TranscriptError {
+			return &TranscriptError{Line: i + 1, Msg: fmt.Sprintf(format, args...)}
+		}
+		first, name, ok := strings.Cut(line, " ")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return errorf("unexpected ls output %q", line)
+		}
+		if _, ok := files[name]; ok || dirs[name] {
+			return errorf("%s is listed twice in %s", name, fs.Cwd.Path())
+		}
+		if first == "dir" {
+			dirs[name] = true
+			continue
+		}
+		size, err := strconv.Atoi(first)
+		if err != nil || size < 0 {
+			return errorf("file %s has size %q, want a number", name, first)
+		}
+		files[name] = size
+	}
+
+	d := fs.Cwd
+	if d.listed {
+		if !maps.Equal(dirs, dirSet(d)) || !maps.Equal(files, d.Files) {
+			return &TranscriptError{Msg: fmt.Sprintf("listing of %s differs from the earlier listing", d.Path())}
+		}
+		return nil
+	}
+	// A directory may only be known from the listing of its parent
+	for name := range dirs {
+		d.Dirs[name] = newDir(name, d)
+	}
+	d.Files = files
+	d.listed = true
+	return nil
+}
+
+// dirSet returns the names of the sub-directories of a directory
+func dirSet(d *Dir) map[string]bool {
+	set := map[string]bool{}
+	for name := range d.Dirs {
+		set[name] = true
+	}
+	return set
+}
+
+// Tree returns a listing of the filesystem in the style of the tree command, with the size of each entry.
+// Directories which were never listed are marked as such.
+func (fs *FS) Tree() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "/ (%d)\n", fs.Root.Size())
+	tree(&sb, fs.Root, "")
+	return sb.String()
+}
+
+// tree writes the entries of a directory in name order, each line starting with a prefix
+func tree(sb *strings.Builder, d *Dir, prefix string) {
+	var names []string
+	for name := range d.Files {
+		names = append(names, name)
+	}
+	for name := range d.Dirs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for i, name := range names {
+		branch, indent := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, indent = "└── ", "    "
+		}
+		if sub, ok := d.Dirs[name]; ok {
+			note := ""
+			if !sub.listed {
+				note = ", not listed"
+			}
+			fmt.Fprintf(sb, "%s%s%s/ (%d%s)\n", prefix, branch, name, sub.Size(), note)
+			tree(sb, sub, prefix+indent)
+		} else {
+			fmt.Fprintf(sb, "%s%s%s (%d)\n", prefix, branch, name, d.Files[name])
+		}
+	}
+}
+
+// Usage is the total size of a directory
+type Usage struct {
+	Path string
+	Size int
+}
+
+// Usage returns the size of every directory, largest first with ties in the order of Walk
+func (fs *FS) Usage() []Usage {
+	var usage []Usage
+	fs.Root.Walk(func(d *Dir) {
+		usage = append(usage, Usage{Path: d.Path(), Size: d.Size()})
+	})
+	slices.SortStableFunc(usage, func(a, b Usage) int {
+		return b.Size - a.Size
+	})
+	return usage
+}
+
+// Du returns a report of the size of every directory in the style of du -h, largest first
+func (fs *FS) Du() string {
+	return fs.du("")
+}
+
+// du returns the report of Du with the directory at a path marked
+func (fs *FS) du(mark string) string {
+	var sb strings.Builder
+	for _, u := range fs.Usage() {
+		fmt.Fprintf(&sb, "%6s  %s", HumanSize(u.Size), u.Path)
+		if u.Path == mark {
+			sb.WriteString("  <- delete")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Report writes the tree and du reports of a filesystem, with the directory to delete marked in the du report
+func Report(w io.Writer, fs *FS, deletePath string) error {
+	_, err := io.WriteString(w, fs.Tree()+"\n"+fs.du(deletePath))
+	return err
+}
+
+// destination is where the package level Export writes, empty unless exporting has been started
+var destination string
+
+// Start begins exporting the filesystems passed to Export to a file, or printing them if the destination is "-"
+func Start(dest string) {
+	destination = dest
+}
+
+// Enabled returns whether filesystems are being exported, so puzzles can avoid building reports which are not needed
+func Enabled() bool {
+	return destination != ""
+}
+
+// Export writes the tree and du reports of a filesystem if exporting has been started
+func Export(fs *FS, deletePath string) error {
+	switch destination {
+	case "":
+		return nil
+	case "-":
+		return Report(os.Stdout, fs, deletePath)
+	}
+	f, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	if err := Report(f, fs, deletePath); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// HumanSize returns a size in bytes with a binary unit, such as "1.5K" or "14M"
+func HumanSize(size int) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return strconv.Itoa(size)
+	}
+	value := float64(size)
+	unit := -1
+	// The unit is chosen after rounding so that a size just under 1M is written as "1.0M" rather than "1024K"
+	for {
+		value /= 1024
+		unit++
+		if unit == len(units)-1 || roundSize(value) < 1024 {
+			break
+		}
+	}
+	value = roundSize(value)
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, units[unit])
+	}
+	return fmt.Sprintf("%.0f%c", value, units[unit])
+}
+
+// roundSize rounds a size to the precision HumanSize writes it with, one decimal place below 10 and none above
+func roundSize(value float64) float64 {
+	if value < 10 {
+		return math.Round(value*10) / 10
+	}
+	return math.Round(value)
+}
@@ -0,0 +1,187 @@
+package vfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const transcript = `$ cd /
+$ ls
+dir a
+14848514 b.txt
+8504156 c.dat
+dir d
+$ cd a
+$ ls
+dir e
+29116 f
+2557 g
+62596 h.lst
+$ cd e
+$ ls
+584 i
+$ cd ..
+$ cd ..
+$ cd d
+$ ls
+4060174 j
+8033020 d.log
+5626152 d.ext
+7214296 k
+`
+
+func TestTree(t *testing.T) {
+	fs, err := Replay(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `/ (48381165)
+├── a/ (94853)
+│   ├── e/ (584)
+│   │   └── i (584)
+│   ├── f (29116)
+│   ├── g (2557)
+│   └── h.lst (62596)
+├── b.txt (14848514)
+├── c.dat (8504156)
+└── d/ (24933642)
+    ├── d.ext (5626152)
+    ├── d.log (8033020)
+    ├── j (4060174)
+    └── k (7214296)
+`
+	if got := fs.Tree(); got != want {
+		t.Errorf("Tree() = \n%s, want\n%s", got, want)
+	}
+}
+
+func TestDu(t *testing.T) {
+	fs, err := Replay(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `   46M  /
+   24M  /d
+   93K  /a
+   584  /a/e
+`
+	if got := fs.Du(); got != want {
+		t.Errorf("Du() = \n%s, want\n%s", got, want)
+	}
+}
+
+func TestCd(t *testing.T) {
+	fs, err := Replay(transcript + "$ cd /a/e\n$ cd ../../d/./\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fs.Cwd.Path(); got != "/d" {
+		t.Errorf("Cwd.Path() = %s, want /d", got)
+	}
+	if err := fs.Cd("../../.."); err != nil || fs.Cwd != fs.Root {
+		t.Errorf("Cd(../../..) = %v in %s, want /", err, fs.Cwd.Path())
+	}
+}
+
+func TestReplayError(t *testing.T) {
+	tests := []struct {
+		name, transcript string
+		line             int
+	}{
+		{name: "unknown directory", transcript: "$ ls\ndir a\n$ cd b\n", line: 3},
+		{name: "unlisted directory", transcript: "$ cd a\n", line: 1},
+		{name: "unknown command", transcript: "$ ls\n$ rm a\n", line: 2},
+		{name: "output without ls", transcript: "dir a\n", line: 1},
+		{name: "listed twice", transcript: "$ ls\ndir a\n1 a\n", line: 3},
+		{name: "bad size", transcript: "$ cd /\n$ ls\nbig a\n", line: 3},
+		{name: "contradictory listing", transcript: "$ ls\n1 a\n$ cd /\n$ ls\n2 a\n", line: 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Replay(tc.transcript)
+			var transcriptErr *TranscriptError
+			if !errors.As(err, &transcriptErr) {
+				t.Fatalf("Replay() error = %v, want a TranscriptError", err)
+			}
+			if transcriptErr.Line != tc.line {
+				t.Errorf("Replay() error at line %d, want %d: %v", transcriptErr.Line, tc.line, err)
+			}
+		})
+	}
+
+	// Listing a directory again the same way is allowed
+	if _, err := Replay(transcript + "$ cd /a\n$ ls\n62596 h.lst\n2557 g\n29116 f\ndir e\n"); err != nil {
+		t.Errorf("Replay() of a repeated listing error: %v", err)
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := map[int]string{
+		0:        "0",
+		1023:     "1023",
+		1536:     "1.5K",
+		94853:    "93K",
+		48381165: "46M",
+		5 << 30:  "5.0G",
+		10188:    "9.9K",
+		10200:    "10K",
+		1048000:  "1023K",
+		1048300:  "1.0M",
+		1048575:  "1.0M",
+	}
+	for size, want := range tests {
+		if got := HumanSize(size); got != want {
+			t.Errorf("HumanSize(%d) = %s, want %s", size, got, want)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	fs, err := Replay(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := Report(&sb, fs, "/d"); err != nil {
+		t.Fatal(err)
+	}
+	want := fs.Tree() + "\n" + strings.Replace(fs.Du(), "/d\n", "/d  <- delete\n", 1)
+	if sb.String() != want {
+		t.Errorf("Report() wrote\n%s, want\n%s", sb.String(), want)
+	}
+}
+
+func TestExport(t *testing.T) {
+	fs, err := Replay(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Export(fs, "/d"); err != nil {
+		t.Fatalf("Export() before Start = %v, want nil", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "tree.txt")
+	Start(path)
+	defer Start("")
+
+	if !Enabled() {
+		t.Fatal("Enabled() = false after Start")
+	}
+	if err := Export(fs, "/d"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := Report(&sb, fs, "/d"); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != sb.String() {
+		t.Errorf("Export() wrote\n%s, want\n%s", data, sb.String())
+	}
+}
@@ -5,227 +5,68 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
-)
-
-type hand struct {
-	cards    [5]int
-	bid      int
-	handType int
-}
-
-func (h *hand) initHandType() {
-	m := map[int]int{}
-	for _, c := range h.cards {
-		m[c] += 1
-	}
-	var counts []int
-	for _, v := range m {
-		counts = append(counts, v)
-	}
-	switch {
-	case len(counts) == 1:
-		h.handType = fiveOfAKind
-
-	case len(counts) == 2 && (counts[0] == 4 || counts[1] == 4):
-		h.handType = fourOfAKind
-
-	case len(counts) == 2 && (counts[0] == 3 || counts[1] == 3):
-		h.handType = fullHouse
-
-	case len(counts) == 3 && (counts[0] == 3 || counts[1] == 3 || counts[2] == 3):
-		h.handType = threeOfAKind
-
-	case len(counts) == 3:
-		h.handType = twoPair
-
-	case len(counts) == 4:
-		h.handType = onePair
-
-	case len(counts) == 5:
-		h.handType = highCard
-	}
-}
-
-func (h *hand) jackToJoker() {
-	for i, c := range h.cards {
-		if c == 11 {
-			h.cards[i] = 1
-		}
-	}
-	h.setJokerHandType()
-}
 
-func (h *hand) setJokerHandType() {
-	m := map[int]int{}
-	for _, c := range h.cards {
-		m[c] += 1
-	}
-	var counts []int
-	for _, v := range m {
-		counts = append(counts, v)
-	}
-	jokers := m[1]
-
-	switch {
-	case len(counts) == 1:
-		h.handType = fiveOfAKind
-
-	case len(counts) == 2 && (counts[0] == 4 || counts[1] == 4):
-		switch jokers {
-		case 1, 4:
-			h.handType = fiveOfAKind
-		default:
-			h.handType = fourOfAKind
-		}
-
-	case len(counts) == 2 && (counts[0] == 3 || counts[1] == 3):
-		switch jokers {
-		case 2, 3:
-			h.handType = fiveOfAKind
-		default:
-			h.handType = fullHouse
-		}
-
-	case len(counts) == 3 && (counts[0] == 3 || counts[1] == 3 || counts[2] == 3):
-		switch jokers {
-		case 1, 3:
-			h.handType = fourOfAKind
-		default:
-			h.handType = threeOfAKind
-		}
-
-	case len(counts) == 3:
-		switch jokers {
-		case 2:
-			h.handType = fourOfAKind
-		case 1:
-			h.handType = fullHouse
-		default:
-			h.handType = twoPair
-		}
-
-	case len(counts) == 4:
-		switch jokers {
-		case 1, 2:
-			h.handType = threeOfAKind
-		default:
-			h.handType = onePair
-		}
-
-	case len(counts) == 5:
-		switch jokers {
-		case 1:
-			h.handType = onePair
-		default:
-			h.handType = highCard
-		}
-	}
-}
-
-// Hand types in ascending strength.
-const (
-	highCard = iota
-	onePair
-	twoPair
-	threeOfAKind
-	fullHouse
-	fourOfAKind
-	fiveOfAKind
+	"github.com/maze-mapper/advent-of-code/2023/camelcards"
 )
 
-type handSorter []hand
-
-func (hs handSorter) Len() int {
-	return len(hs)
-}
-
-func (hs handSorter) Swap(i, j int) {
-	hs[i], hs[j] = hs[j], hs[i]
-}
-
-func (hs handSorter) Less(i, j int) bool {
-	if hs[i].handType == hs[j].handType {
-		for k := 0; k < 5; k++ {
-			if hs[i].cards[k] != hs[j].cards[k] {
-				return hs[i].cards[k] < hs[j].cards[k]
-			}
-		}
-		return false
-	}
-	return hs[i].handType < hs[j].handType
-}
-
-func cardToInt(r rune) (int, error) {
-	switch r {
-	case 'A':
-		return 14, nil
-	case 'K':
-		return 13, nil
-	case 'Q':
-		return 12, nil
-	case 'J':
-		return 11, nil
-	case 'T':
-		return 10, nil
-	default:
-		n, err := strconv.Atoi(string(r))
-		if err != nil {
-			return 0, err
-		}
-		return n, nil
-	}
+type hand struct {
+	cards string
+	bid   int
 }
 
-func parseData(data []byte) []hand {
+func parseData(data []byte) ([]hand, error) {
 	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
 	hands := make([]hand, len(lines))
 	for i, line := range lines {
-		parts := strings.Split(line, " ")
-		cards := [5]int{}
-		for i, r := range parts[0] {
-			n, err := cardToInt(r)
-			if err != nil {
-				log.Fatal(err)
-			}
-			cards[i] = n
+		cards, bid, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %q is not a hand and a bid", line)
 		}
-		bid, err := strconv.Atoi(parts[1])
+		n, err := strconv.Atoi(bid)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		h := hand{
-			cards: cards,
-			bid:   bid,
-		}
-		h.initHandType()
-		hands[i] = h
+		hands[i] = hand{cards: cards, bid: n}
 	}
-	return hands
+	return hands, nil
 }
 
-func part1(hands handSorter) int {
-	sort.Sort(hands)
-	total := 0
-	for i, h := range hands {
-		total += (i + 1) * h.bid
+// winnings returns the total of the bids of the hands each multiplied by the rank of its hand under the rules
+func winnings(hands []hand, rules camelcards.Rules) (int, error) {
+	type ranked struct {
+		camelcards.Hand
+		bid int
 	}
-	return total
-}
-
-func part2(hands handSorter) int {
+	if err := rules.Validate(); err != nil {
+		return 0, err
+	}
+	rankedHands := make([]ranked, len(hands))
 	for i, h := range hands {
-		h.jackToJoker()
-		hands[i] = h
+		evaluated, err := rules.Evaluate(h.cards)
+		if err != nil {
+			return 0, err
+		}
+		rankedHands[i] = ranked{Hand: evaluated, bid: h.bid}
 	}
-	sort.Sort(hands)
+	slices.SortStableFunc(rankedHands, func(a, b ranked) int {
+		return camelcards.Compare(a.Hand, b.Hand)
+	})
 	total := 0
-	for i, h := range hands {
+	for i, h := range rankedHands {
 		total += (i + 1) * h.bid
 	}
-	return total
+	return total, nil
+}
+
+func part1(hands []hand) (int, error) {
+	return winnings(hands, camelcards.Camel)
+}
+
+func part2(hands []hand) (int, error) {
+	return winnings(hands, camelcards.Joker)
 }
 
 func Run(inputFile string) {
@@ -233,11 +74,20 @@ func Run(inputFile string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	hands := parseData(data)
+	hands, err := parseData(data)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	p1 := part1(hands)
+	p1, err := part1(hands)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(hands)
+	p2, err := part2(hands)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 2:", p2)
 }
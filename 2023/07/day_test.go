@@ -0,0 +1,56 @@
+package day7
+
+import (
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/2023/camelcards"
+)
+
+var input = []byte(`32T3K 765
+T55J5 684
+KK677 28
+KTJJT 220
+QQQJA 483
+`)
+
+func TestPart1(t *testing.T) {
+	hands, err := parseData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 6440
+	got, err := part1(hands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("part1(%s) = %d, want %d", input, got, want)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	hands, err := parseData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 5905
+	got, err := part2(hands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("part2(%s) = %d, want %d", input, got, want)
+	}
+}
+
+func TestWinningsInvalidRules(t *testing.T) {
+	hands, err := parseData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := camelcards.Camel
+	rules.Order = "23456789TJQKAA"
+	if _, err := winnings(hands, rules); err == nil {
+		t.Errorf("winnings() with a card repeated in the order returned no error")
+	}
+}
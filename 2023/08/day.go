@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type network struct {
@@ -47,6 +49,28 @@ func parseData(data []byte) network {
 	return n
 }
 
+// toDOT returns the network as a graph with the left and right instructions as edge labels.
+// Starting nodes, ending in A, are drawn as boxes and ending nodes, ending in Z, as double circles.
+func toDOT(n network) *dot.Graph {
+	g := dot.New("network", true)
+	for node, left := range n.lefts {
+		right := n.rights[node]
+		if left == right {
+			g.Edge(node, left, dot.Attrs{"label": "L,R"})
+		} else {
+			g.Edge(node, left, dot.Attrs{"label": "L"})
+			g.Edge(node, right, dot.Attrs{"label": "R"})
+		}
+		switch {
+		case strings.HasSuffix(node, "A"):
+			g.Node(node, dot.Attrs{"shape": "box"})
+		case strings.HasSuffix(node, "Z"):
+			g.Node(node, dot.Attrs{"shape": "doublecircle"})
+		}
+	}
+	return g
+}
+
 func part1(input network) int {
 	current := "AAA"
 	steps := 0
@@ -137,6 +161,11 @@ func Run(inputFile string) {
 		log.Fatal(err)
 	}
 	n := parseData(data)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(n)); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	p1 := part1(n)
 	fmt.Println("Part 1:", p1)
@@ -0,0 +1,22 @@
+package day8
+
+import (
+	"testing"
+)
+
+var input = []byte(`LLR
+
+AAA = (BBB, BBB)
+BBB = (AAA, ZZZ)
+ZZZ = (ZZZ, ZZZ)`)
+
+func TestToDOT(t *testing.T) {
+	g := toDOT(parseData(input))
+	if got := len(g.Nodes()); got != 3 {
+		t.Errorf("toDOT() has %d nodes, want 3", got)
+	}
+	// Nodes whose left and right are the same have a single edge
+	if got := g.Edges(); got != 4 {
+		t.Errorf("toDOT() has %d edges, want 4", got)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 func parseData(data []byte) [][]int {
@@ -78,6 +80,7 @@ type Item struct {
 	direction            cruicibleDirection
 	stepsInSameDirection int
 	heatLoss             int
+	previous             *Item
 	// The index is needed by update and is maintained by the heap.Interface methods.
 	index int // The index of the item in the heap.
 }
@@ -117,15 +120,41 @@ func (pq *PriorityQueue) Pop() any {
 	return item
 }
 
-func part1(area [][]int) int {
+// path returns the positions of the crucible from the top left corner up to the item.
+func (item *Item) path() []coordinates.Coord {
+	path := []coordinates.Coord{}
+	for it := item; it != nil; it = it.previous {
+		path = append(path, it.pos)
+	}
+	path = append(path, coordinates.Coord{})
+	slices.Reverse(path)
+	return path
+}
+
+func part1(area [][]int) (int, []coordinates.Coord) {
 	return solve(area, 0, 3)
 }
 
-func part2(area [][]int) int {
+func part2(area [][]int) (int, []coordinates.Coord) {
 	return solve(area, 4, 10)
 }
 
-func solve(area [][]int, minStraightSteps, maxStraightSteps int) int {
+// drawPath draws the path of the crucible over the heat loss of each block.
+func drawPath(label string, area [][]int, path []coordinates.Coord) {
+	grid := make([][]rune, len(area))
+	for i, row := range area {
+		grid[i] = make([]rune, len(row))
+		for j, n := range row {
+			grid[i][j] = rune('0' + n)
+		}
+	}
+	if err := overlay.Draw(label, grid, overlay.Arrows(path)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// solve returns the least heat loss of a path from the top left to the bottom right corner, and the path.
+func solve(area [][]int, minStraightSteps, maxStraightSteps int) (int, []coordinates.Coord) {
 	goal := coordinates.Coord{X: len(area[len(area)-1]) - 1, Y: len(area) - 1}
 	pq := make(PriorityQueue, 2)
 	pq[0] = &Item{
@@ -152,7 +181,7 @@ func solve(area [][]int, minStraightSteps, maxStraightSteps int) int {
 	for pq.Len() > 0 {
 		item := heap.Pop(&pq).(*Item)
 		if item.pos == goal && item.stepsInSameDirection >= minStraightSteps {
-			return item.heatLoss
+			return item.heatLoss, item.path()
 		}
 
 		if item.stepsInSameDirection < maxStraightSteps {
@@ -165,6 +194,7 @@ func solve(area [][]int, minStraightSteps, maxStraightSteps int) int {
 					direction:            item.direction,
 					stepsInSameDirection: item.stepsInSameDirection + 1,
 					heatLoss:             heatLoss,
+					previous:             item,
 				}
 				key := fmt.Sprintf("%d %d %d %d", it.pos.X, it.pos.Y, it.direction, it.stepsInSameDirection)
 				if v, ok := visited[key]; !ok || it.heatLoss < v {
@@ -184,6 +214,7 @@ func solve(area [][]int, minStraightSteps, maxStraightSteps int) int {
 					direction:            d1,
 					stepsInSameDirection: 1,
 					heatLoss:             heatLoss,
+					previous:             item,
 				}
 				key := fmt.Sprintf("%d %d %d %d", it.pos.X, it.pos.Y, it.direction, it.stepsInSameDirection)
 				if v, ok := visited[key]; !ok || it.heatLoss < v {
@@ -199,6 +230,7 @@ func solve(area [][]int, minStraightSteps, maxStraightSteps int) int {
 					direction:            d2,
 					stepsInSameDirection: 1,
 					heatLoss:             heatLoss,
+					previous:             item,
 				}
 				key := fmt.Sprintf("%d %d %d %d", it.pos.X, it.pos.Y, it.direction, it.stepsInSameDirection)
 				if v, ok := visited[key]; !ok || it.heatLoss < v {
@@ -208,7 +240,7 @@ func solve(area [][]int, minStraightSteps, maxStraightSteps int) int {
 			}
 		}
 	}
-	return 0
+	return 0, nil
 }
 
 func Run(inputFile string) {
@@ -218,9 +250,14 @@ func Run(inputFile string) {
 	}
 	area := parseData(data)
 
-	p1 := part1(area)
+	p1, path1 := part1(area)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(area)
+	p2, path2 := part2(area)
 	fmt.Println("Part 2:", p2)
+
+	if overlay.Enabled() {
+		drawPath("part1", area, path1)
+		drawPath("part2", area, path2)
+	}
 }
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type pulseFreq int
@@ -173,6 +176,29 @@ func parseData(data []byte) map[string]module {
 	return modules
 }
 
+// toDOT returns the modules as a graph of the pulses they send.
+// Flip-flops are drawn as boxes, conjunctions as diamonds and modules which only receive pulses as plain text.
+func toDOT(modules map[string]module) *dot.Graph {
+	g := dot.New("modules", true)
+	for name, m := range modules {
+		switch m.(type) {
+		case *flipFlopModule:
+			g.Node(name, dot.Attrs{"label": "%" + name, "shape": "box"})
+		case *conjunctionModule:
+			g.Node(name, dot.Attrs{"label": "&" + name, "shape": "diamond"})
+		case *broadcastModule:
+			g.Node(name, dot.Attrs{"shape": "doublecircle"})
+		}
+		for _, dest := range m.outputs() {
+			g.Edge(name, dest, nil)
+			if _, ok := modules[dest]; !ok {
+				g.Node(dest, dot.Attrs{"shape": "plaintext"})
+			}
+		}
+	}
+	return g
+}
+
 func part1(modules map[string]module) int {
 	counts := map[pulseFreq]int{
 		lowPulse:  0,
@@ -201,8 +227,93 @@ func part1(modules map[string]module) int {
 	return counts[lowPulse] * counts[highPulse]
 }
 
-func part2(modules map[string]module) int {
-	for i := 0; ; i++ {
+// maxPresses is the number of button presses to simulate while looking for the period of each sub-network.
+const maxPresses = 1 << 16
+
+// inputsOf returns the names of the modules which send pulses to the named module, in sorted order.
+func inputsOf(modules map[string]module, name string) []string {
+	var inputs []string
+	for source, m := range modules {
+		if slices.Contains(m.outputs(), name) {
+			inputs = append(inputs, source)
+		}
+	}
+	slices.Sort(inputs)
+	return inputs
+}
+
+// reachable returns the modules reachable from a module without passing through the stop module.
+func reachable(modules map[string]module, start, stop string) map[string]bool {
+	seen := map[string]bool{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if name == stop || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if m, ok := modules[name]; ok {
+			queue = append(queue, m.outputs()...)
+		}
+	}
+	return seen
+}
+
+// findCounters checks that rx is fed by a single conjunction module whose inputs are each driven by a separate
+// sub-network started by the broadcaster, and returns the name of the conjunction module and its inputs.
+func findCounters(modules map[string]module) (string, []string, error) {
+	feeders := inputsOf(modules, "rx")
+	if len(feeders) != 1 {
+		return "", nil, fmt.Errorf("rx has %d inputs, expected 1", len(feeders))
+	}
+	feeder := feeders[0]
+	if _, ok := modules[feeder].(*conjunctionModule); !ok {
+		return "", nil, fmt.Errorf("rx is fed by %s which is not a conjunction module", feeder)
+	}
+	counters := inputsOf(modules, feeder)
+
+	broadcaster, ok := modules["broadcaster"]
+	if !ok {
+		return "", nil, fmt.Errorf("no broadcaster module")
+	}
+	starts := broadcaster.outputs()
+	if len(starts) != len(counters) {
+		return "", nil, fmt.Errorf("broadcaster starts %d sub-networks but %s has %d inputs", len(starts), feeder, len(counters))
+	}
+
+	// Each sub-network must be separate from the others and drive exactly one input of the feeder.
+	owner := map[string]string{}
+	driven := map[string]bool{}
+	for _, start := range starts {
+		var drives []string
+		for name := range reachable(modules, start, feeder) {
+			if other, ok := owner[name]; ok {
+				return "", nil, fmt.Errorf("module %s is shared by the sub-networks starting at %s and %s", name, other, start)
+			}
+			owner[name] = start
+			if slices.Contains(counters, name) {
+				drives = append(drives, name)
+			}
+		}
+		if len(drives) != 1 {
+			return "", nil, fmt.Errorf("sub-network starting at %s drives %d inputs of %s, expected 1", start, len(drives), feeder)
+		}
+		driven[drives[0]] = true
+	}
+	if len(driven) != len(counters) {
+		return "", nil, fmt.Errorf("only %d of the %d inputs of %s are driven by a sub-network", len(driven), len(counters), feeder)
+	}
+
+	return feeder, counters, nil
+}
+
+// highPulsePeriods presses the button and returns the number of presses between each counter sending a high pulse to
+// the feeder. Each counter must first send a high pulse after a whole period, so that it repeats from the start.
+func highPulsePeriods(modules map[string]module, feeder string, counters []string) (map[string]int, error) {
+	first := map[string]int{}
+	periods := map[string]int{}
+	for press := 1; press <= maxPresses && len(periods) < len(counters); press++ {
 		queue := []pulseInfo{
 			{
 				pulse:  lowPulse,
@@ -210,24 +321,61 @@ func part2(modules map[string]module) int {
 				dest:   "broadcaster",
 			},
 		}
-		// TODO: brute force doesn't work.
-		// Instead need to find the LCM of when the four inputs to the comparator module send a high pulse.
 		for len(queue) > 0 {
 			pi := queue[0]
 			queue[0] = pulseInfo{}
 			queue = queue[1:]
-			// fmt.Println(pi.String())
-			if pi.dest == "rx" && pi.pulse == lowPulse {
-				return i
-			}
-			if pi.dest == "rs" && pi.pulse == highPulse {
-				fmt.Println(i, pi.String())
+			if _, known := periods[pi.source]; pi.dest == feeder && pi.pulse == highPulse && !known {
+				f, seen := first[pi.source]
+				switch {
+				case !seen:
+					first[pi.source] = press
+				case press == f:
+					// Several high pulses may be sent on one press.
+				case press == 2*f:
+					periods[pi.source] = f
+				default:
+					return nil, fmt.Errorf("%s sent high pulses after %d and %d presses so does not repeat from the start", pi.source, f, press)
+				}
 			}
 			if destMod, ok := modules[pi.dest]; ok {
 				destMod.sendPulse(pi.pulse, pi.source, modules, &queue)
 			}
 		}
 	}
+	if len(periods) != len(counters) {
+		return nil, fmt.Errorf("found the period of %d of %d counters after %d presses", len(periods), len(counters), maxPresses)
+	}
+	return periods, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int) int {
+	return a / gcd(a, b) * b
+}
+
+// part2 finds the conjunction module feeding rx and the counters feeding it.
+// rx receives a low pulse when every counter sends a high pulse on the same press, which is the LCM of their periods.
+func part2(modules map[string]module) (int, error) {
+	feeder, counters, err := findCounters(modules)
+	if err != nil {
+		return 0, err
+	}
+	periods, err := highPulsePeriods(modules, feeder, counters)
+	if err != nil {
+		return 0, err
+	}
+	presses := 1
+	for _, counter := range counters {
+		presses = lcm(presses, periods[counter])
+	}
+	return presses, nil
 }
 
 func Run(inputFile string) {
@@ -236,10 +384,19 @@ func Run(inputFile string) {
 		log.Fatal(err)
 	}
 	modules := parseData(data)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(modules)); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	p1 := part1(modules)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(modules)
+	// Part 1 changes the state of the modules so start again.
+	p2, err := part2(parseData(data))
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 2:", p2)
 }
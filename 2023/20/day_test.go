@@ -0,0 +1,139 @@
+package day20
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var input1 = []byte(`broadcaster -> a, b, c
+%a -> b
+%b -> c
+%c -> inv
+&inv -> a`)
+
+var input2 = []byte(`broadcaster -> a
+%a -> inv, con
+&inv -> b
+%b -> con
+&con -> output`)
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  int
+	}{
+		{name: "Example_1", input: input1, want: 32000000},
+		{name: "Example_2", input: input2, want: 11687500},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := part1(parseData(tc.input))
+			if got != tc.want {
+				t.Errorf("part1(%s) = %d, want %d", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+// counterNetwork returns a network where each period has a binary counter of flip-flops which resets when it reaches
+// the period, sending a high pulse through an inverter to the conjunction module feeding rx.
+func counterNetwork(periods []int) []byte {
+	var starts, lines []string
+	for i, period := range periods {
+		name := fmt.Sprintf("%c", 'a'+i)
+		bits := 0
+		for 1<<bits <= period {
+			bits++
+		}
+		con := name + "con"
+		conOutputs := []string{name + "inv", name + "0"}
+		for bit := 0; bit < bits; bit++ {
+			var outputs []string
+			if bit < bits-1 {
+				outputs = append(outputs, fmt.Sprintf("%s%d", name, bit+1))
+			}
+			if period&(1<<bit) != 0 {
+				outputs = append(outputs, con)
+			} else {
+				conOutputs = append(conOutputs, fmt.Sprintf("%s%d", name, bit))
+			}
+			lines = append(lines, fmt.Sprintf("%%%s%d -> %s", name, bit, strings.Join(outputs, ", ")))
+		}
+		lines = append(lines,
+			fmt.Sprintf("&%s -> %s", con, strings.Join(conOutputs, ", ")),
+			fmt.Sprintf("&%sinv -> feed", name),
+		)
+		starts = append(starts, name+"0")
+	}
+	lines = append(lines, "broadcaster -> "+strings.Join(starts, ", "), "&feed -> rx")
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// pressesUntilRx presses the button until rx receives a low pulse.
+func pressesUntilRx(modules map[string]module) int {
+	for press := 1; ; press++ {
+		queue := []pulseInfo{{pulse: lowPulse, source: "button", dest: "broadcaster"}}
+		for len(queue) > 0 {
+			pi := queue[0]
+			queue = queue[1:]
+			if pi.dest == "rx" && pi.pulse == lowPulse {
+				return press
+			}
+			if destMod, ok := modules[pi.dest]; ok {
+				destMod.sendPulse(pi.pulse, pi.source, modules, &queue)
+			}
+		}
+	}
+}
+
+func TestPart2(t *testing.T) {
+	tests := [][]int{
+		{3, 5},
+		{7, 11, 13},
+		{9, 15, 21},
+	}
+	for _, periods := range tests {
+		t.Run(fmt.Sprint(periods), func(t *testing.T) {
+			input := counterNetwork(periods)
+			want := pressesUntilRx(parseData(input))
+			got, err := part2(parseData(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("part2(%s) = %d, want %d", input, got, want)
+			}
+		})
+	}
+}
+
+func TestPart2Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{name: "No_Rx", input: input1},
+		{name: "Shared_Sub_Network", input: []byte("broadcaster -> a, b\n%a -> c\n%b -> c\n&c -> x, y\n&x -> feed\n&y -> feed\n&feed -> rx")},
+		{name: "Rx_Fed_By_Flip_Flop", input: []byte("broadcaster -> a\n%a -> rx")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := part2(parseData(tc.input)); err == nil {
+				t.Errorf("part2(%s) returned no error", tc.input)
+			}
+		})
+	}
+}
+
+func TestToDOT(t *testing.T) {
+	g := toDOT(parseData(input2))
+	// The output module receives pulses but is not configured
+	if got := len(g.Nodes()); got != 6 {
+		t.Errorf("toDOT() has %d nodes, want 6", got)
+	}
+	if got := g.Edges(); got != 6 {
+		t.Errorf("toDOT() has %d edges, want 6", got)
+	}
+}
@@ -56,13 +56,213 @@ func part1(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int,
 	return len(frontier)
 }
 
-func part2(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int, xMax int, maxSteps int) int {
+// maxStableTiles is the largest number of tiles from the start tile to search before the distances to each tile
+// are expected to grow by the same number of steps per tile.
+const maxStableTiles = 8
+
+// tileDistances holds the fewest steps to every cell of the tiles within a number of tiles of the start tile.
+type tileDistances struct {
+	width, height, tiles int
+	dist                 []int
+}
+
+// newTileDistances finds the fewest steps from the start to every cell within the given number of tiles of the start
+// tile using a breadth first search, with -1 for cells which cannot be reached.
+func newTileDistances(start coordinates.Coord, rocks map[coordinates.Coord]bool, width, height, tiles int) *tileDistances {
+	td := &tileDistances{width: width, height: height, tiles: tiles}
+	fullWidth := (2*tiles + 1) * width
+	fullHeight := (2*tiles + 1) * height
+	td.dist = make([]int, fullWidth*fullHeight)
+	for i := range td.dist {
+		td.dist[i] = -1
+	}
+
+	origin := coordinates.Coord{X: tiles*width + start.X, Y: tiles*height + start.Y}
+	td.dist[origin.Y*fullWidth+origin.X] = 0
+	queue := []coordinates.Coord{origin}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		d := td.dist[c.Y*fullWidth+c.X]
+		neighbours := []coordinates.Coord{
+			{X: c.X, Y: c.Y - 1},
+			{X: c.X + 1, Y: c.Y},
+			{X: c.X, Y: c.Y + 1},
+			{X: c.X - 1, Y: c.Y},
+		}
+		for _, n := range neighbours {
+			if n.X < 0 || n.Y < 0 || n.X >= fullWidth || n.Y >= fullHeight {
+				continue
+			}
+			if rocks[coordinates.Coord{X: n.X % width, Y: n.Y % height}] {
+				continue
+			}
+			if idx := n.Y*fullWidth + n.X; td.dist[idx] == -1 {
+				td.dist[idx] = d + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+	return td
+}
+
+// at returns the fewest steps to a cell of the tile i tiles right and j tiles down from the start tile.
+func (td *tileDistances) at(i, j int, c coordinates.Coord) int {
+	x := (td.tiles+i)*td.width + c.X
+	y := (td.tiles+j)*td.height + c.Y
+	return td.dist[y*(2*td.tiles+1)*td.width+x]
+}
+
+// growth holds the extra steps needed to reach a cell in each tile further away from the start tile, indexed by the
+// direction moved of -1 or 1 tiles horizontally and vertically.
+type growth struct {
+	horizontal, vertical map[int]int
+}
+
+// stable returns whether, beyond k tiles from the start tile, the distance to a cell grows by the same number of steps
+// for each tile further away, so that distances to far tiles follow from those k tiles away.
+// Distances are checked for two further tiles in each direction and diagonally.
+func (td *tileDistances) stable(k int, cells []coordinates.Coord) (growth, bool) {
+	g := growth{horizontal: map[int]int{}, vertical: map[int]int{}}
+	if len(cells) == 0 {
+		return g, false
+	}
+	for _, s := range []int{-1, 1} {
+		g.horizontal[s] = td.at(s*(k+1), 0, cells[0]) - td.at(s*k, 0, cells[0])
+		g.vertical[s] = td.at(0, s*(k+1), cells[0]) - td.at(0, s*k, cells[0])
+	}
+
+	for _, c := range cells {
+		for _, s := range []int{-1, 1} {
+			for j := -k - 2; j <= k+2; j++ {
+				for m := 1; m <= 2; m++ {
+					if td.at(s*(k+m), j, c) != td.at(s*k, j, c)+m*g.horizontal[s] ||
+						td.at(j, s*(k+m), c) != td.at(j, s*k, c)+m*g.vertical[s] {
+						return g, false
+					}
+				}
+			}
+		}
+		for _, si := range []int{-1, 1} {
+			for _, sj := range []int{-1, 1} {
+				corner := td.at(si*k, sj*k, c)
+				for m := 0; m <= 2; m++ {
+					for n := 0; n <= 2; n++ {
+						if td.at(si*(k+m), sj*(k+n), c) != corner+m*g.horizontal[si]+n*g.vertical[sj] {
+							return g, false
+						}
+					}
+				}
+			}
+		}
+	}
+	return g, true
+}
+
+// countLine returns the number of tiles in a straight line, one tile further away each time, in which a cell is reached
+// on the last step given the remaining steps after reaching it in the nearest tile and the extra steps for each further
+// tile.
+func countLine(remaining, step int) int {
+	if remaining < step {
+		return 0
+	}
+	n := remaining / step
+	if step%2 == 0 {
+		if remaining%2 == 0 {
+			return n
+		}
+		return 0
+	}
+	// Only every other tile has the right parity.
+	if remaining%2 == 1 {
+		return (n + 1) / 2
+	}
+	return n / 2
+}
+
+// countQuadrant returns the number of tiles diagonally beyond a corner tile in which a cell is reached on the last step
+// given the remaining steps after reaching it in the corner tile and the extra steps for each tile further across and
+// down.
+func countQuadrant(remaining, across, down int) int {
+	count := 0
+	for m := 1; m*across+down <= remaining; m++ {
+		count += countLine(remaining-m*across, down)
+	}
+	return count
+}
+
+// countReachable returns the number of garden plots on the infinitely tiled map which can be reached in exactly the
+// given number of steps. Plots are reachable if their distance from the start is no more than the steps and has the
+// same parity. Distances are found to the tiles near the start tile, while the number of further tiles in which each
+// plot is reachable is counted by adding the growth in distance for each tile further away.
+// Diagonal tiles are assumed to be reached by moving across and then down, which holds when some row and some column
+// of the grid are free of rocks. An error is returned if the distances do not settle in to this pattern.
+func countReachable(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int, xMax int, steps int) (int, error) {
+	width, height := xMax+1, yMax+1
+	reachable := func(d int) bool {
+		return d >= 0 && d <= steps && (steps-d)%2 == 0
+	}
+
+	for k := 1; k <= maxStableTiles; k++ {
+		// Keep a margin of tiles around those used so their distances are not affected by the edge of the search.
+		td := newTileDistances(start, rocks, width, height, k+4)
+
+		var cells []coordinates.Coord
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				c := coordinates.Coord{X: x, Y: y}
+				if td.at(0, 0, c) >= 0 {
+					cells = append(cells, c)
+				}
+			}
+		}
+		g, ok := td.stable(k, cells)
+		if !ok {
+			continue
+		}
+
+		total := 0
+		quadrants := map[[3]int]int{}
+		for _, c := range cells {
+			for i := -k; i <= k; i++ {
+				for j := -k; j <= k; j++ {
+					if reachable(td.at(i, j, c)) {
+						total++
+					}
+				}
+			}
+			for _, s := range []int{-1, 1} {
+				for j := -k; j <= k; j++ {
+					total += countLine(steps-td.at(s*k, j, c), g.horizontal[s])
+					total += countLine(steps-td.at(j, s*k, c), g.vertical[s])
+				}
+			}
+			for _, si := range []int{-1, 1} {
+				for _, sj := range []int{-1, 1} {
+					key := [3]int{steps - td.at(si*k, sj*k, c), g.horizontal[si], g.vertical[sj]}
+					n, ok := quadrants[key]
+					if !ok {
+						n = countQuadrant(key[0], key[1], key[2])
+						quadrants[key] = n
+					}
+					total += n
+				}
+			}
+		}
+		return total, nil
+	}
+	return 0, fmt.Errorf("distances did not grow regularly within %d tiles of the start", maxStableTiles)
+}
+
+// quadraticShortcut fits a quadratic through the number of plots reached after the steps to the edge of the start
+// tile and one and two tiles further. It is only valid for a square grid with the start in an empty row and column.
+func quadraticShortcut(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int, xMax int, maxSteps int) (int, bool) {
 	if yMax != xMax {
-		log.Fatal("Expecting a square grid")
+		return 0, false
 	}
 	for c := range rocks {
 		if c.X == start.X || c.Y == start.Y {
-			log.Fatal("Algorithm assumes the start point is in an empty row and column")
+			return 0, false
 		}
 	}
 	gridSize := xMax + 1
@@ -74,15 +274,12 @@ func part2(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int,
 	got := make([]int, len(points))
 
 	frontier := map[coordinates.Coord]bool{start: true}
-	for step := 0; step < maxSteps; step++ {
+	for step := 0; step <= points[len(points)-1]; step++ {
 		for i, p := range points {
 			if step == p {
 				got[i] = len(frontier)
 			}
 		}
-		if got[len(got)-1] != 0 {
-			break
-		}
 		newFrontier := map[coordinates.Coord]bool{}
 		for c := range frontier {
 			neighbours := []coordinates.Coord{
@@ -119,12 +316,20 @@ func part2(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int,
 	c := got[0]
 	a := (got[2] + c - 2*got[1]) / 2
 	b := got[1] - c - a
-	fmt.Printf("a=%d b=%d c=%d\n", a, b, c)
 
 	n := (maxSteps - offset) / gridSize
-	ans := (a * n * n) + (b * n) + c
+	return (a * n * n) + (b * n) + c, true
+}
 
-	return ans
+func part2(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int, xMax int, maxSteps int) (int, error) {
+	ans, err := countReachable(start, rocks, yMax, xMax, maxSteps)
+	if err != nil {
+		return 0, err
+	}
+	if quadratic, ok := quadraticShortcut(start, rocks, yMax, xMax, maxSteps); ok && quadratic != ans {
+		return 0, fmt.Errorf("counted %d plots but the quadratic shortcut gives %d", ans, quadratic)
+	}
+	return ans, nil
 }
 
 func Run(inputFile string) {
@@ -137,6 +342,9 @@ func Run(inputFile string) {
 	p1 := part1(start, rocks, yMax, xMax, 64)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(start, rocks, yMax, xMax, 26501365)
+	p2, err := part2(start, rocks, yMax, xMax, 26501365)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 2:", p2)
 }
@@ -0,0 +1,112 @@
+package day21
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+var input = []byte(`...........
+.....###.#.
+.###.##..#.
+..#.#...#..
+....#.#....
+.##..S####.
+.##..#...#.
+.......##..
+.##.#.####.
+.##..##.##.
+...........`)
+
+func TestPart1(t *testing.T) {
+	want := 16
+	start, rocks, yMax, xMax := parseData(input)
+	got := part1(start, rocks, yMax, xMax, 6)
+	if got != want {
+		t.Errorf("part1(%s, 6) = %d, want %d", input, got, want)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		steps, want int
+	}{
+		{steps: 6, want: 16},
+		{steps: 10, want: 50},
+		{steps: 50, want: 1594},
+		{steps: 100, want: 6536},
+		{steps: 500, want: 167004},
+		{steps: 1000, want: 668697},
+		{steps: 5000, want: 16733044},
+	}
+	start, rocks, yMax, xMax := parseData(input)
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.steps), func(t *testing.T) {
+			got, err := part2(start, rocks, yMax, xMax, tc.steps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("part2(%s, %d) = %d, want %d", input, tc.steps, got, tc.want)
+			}
+		})
+	}
+}
+
+// bruteForce counts the plots reached in exactly the given number of steps by stepping every plot on the frontier.
+func bruteForce(start coordinates.Coord, rocks map[coordinates.Coord]bool, yMax int, xMax int, steps int) int {
+	width, height := xMax+1, yMax+1
+	frontier := map[coordinates.Coord]bool{start: true}
+	for step := 0; step < steps; step++ {
+		newFrontier := map[coordinates.Coord]bool{}
+		for c := range frontier {
+			for _, n := range []coordinates.Coord{{X: c.X, Y: c.Y - 1}, {X: c.X + 1, Y: c.Y}, {X: c.X, Y: c.Y + 1}, {X: c.X - 1, Y: c.Y}} {
+				wrapped := coordinates.Coord{X: ((n.X % width) + width) % width, Y: ((n.Y % height) + height) % height}
+				if !rocks[wrapped] {
+					newFrontier[n] = true
+				}
+			}
+		}
+		frontier = newFrontier
+	}
+	return len(frontier)
+}
+
+func TestPart2Layouts(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		steps int
+	}{
+		{
+			// The quadratic shortcut is valid for this layout so is also checked.
+			name:  "Centred_Start",
+			input: []byte(".......\n.#...#.\n..#.#..\n...S...\n.#...#.\n..#....\n......."),
+			steps: 3 + 7*6,
+		},
+		{
+			name:  "Rectangular_Grid",
+			input: []byte("..#......\n.S..##...\n....#..#.\n.........\n..#...#.."),
+			steps: 57,
+		},
+		{
+			name:  "Enclosed_Plot",
+			input: []byte("....#....\n...#.#...\n....#....\n.........\n..S......"),
+			steps: 40,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start, rocks, yMax, xMax := parseData(tc.input)
+			want := bruteForce(start, rocks, yMax, xMax, tc.steps)
+			got, err := part2(start, rocks, yMax, xMax, tc.steps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("part2(%s, %d) = %d, want %d", tc.input, tc.steps, got, want)
+			}
+		})
+	}
+}
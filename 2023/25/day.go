@@ -7,6 +7,8 @@ import (
 	"math/rand"
 	"os"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type wire struct {
@@ -98,7 +100,20 @@ func dfs(p string, g graph, visited map[string]bool) {
 	}
 }
 
-func part1(wires []wire) int {
+// toDOT returns the wires as a graph with the cut wires highlighted.
+func toDOT(wires []wire, cut []wire) *dot.Graph {
+	g := dot.New("wires", false)
+	for _, w := range wires {
+		g.Edge(w.originalSrc, w.originalDst, nil)
+	}
+	for _, w := range cut {
+		g.HighlightEdge(w.originalSrc, w.originalDst)
+	}
+	return g
+}
+
+// part1 returns the product of the sizes of the two groups left by cutting three wires, and the wires cut.
+func part1(wires []wire) (int, []wire) {
 	minCut := len(wires)
 	var edges []wire
 	for minCut != 3 {
@@ -121,7 +136,7 @@ loop:
 	visited := map[string]bool{}
 	dfs(remainingWires[0].src, g, visited)
 
-	return len(visited) * (len(g) - len(visited))
+	return len(visited) * (len(g) - len(visited)), edges
 }
 
 func Run(inputFile string) {
@@ -131,6 +146,11 @@ func Run(inputFile string) {
 	}
 	hailstones := parseData(data)
 
-	p1 := part1(hailstones)
+	p1, cut := part1(hailstones)
 	fmt.Println("Part 1:", p1)
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(hailstones, cut)); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
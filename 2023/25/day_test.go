@@ -0,0 +1,40 @@
+package day25
+
+import (
+	"reflect"
+	"testing"
+)
+
+var input = []byte(`jqt: rhn xhk nvd
+rsh: frs pzl lsr
+xhk: hfx
+cmg: qnr nvd lhk bvb
+rhn: xhk bvb hfx
+bvb: xhk hfx
+pzl: lsr hfx nvd
+qnr: nvd
+ntq: jqt hfx bvb xhk
+nvd: lhk
+lsr: lhk
+rzs: qnr cmg lsr rsh
+frs: qnr lhk lsr`)
+
+func TestToDOT(t *testing.T) {
+	// The cut is given in the opposite direction to the wires in the input, which are matched in either direction
+	cut := []wire{
+		{originalSrc: "hfx", originalDst: "pzl"},
+		{originalSrc: "bvb", originalDst: "cmg"},
+		{originalSrc: "nvd", originalDst: "jqt"},
+	}
+	g := toDOT(parseData(input), cut)
+	if got := len(g.Nodes()); got != 15 {
+		t.Errorf("toDOT() has %d nodes, want 15", got)
+	}
+	if got := g.Edges(); got != 33 {
+		t.Errorf("toDOT() has %d edges, want 33", got)
+	}
+	want := [][2]string{{"jqt", "nvd"}, {"cmg", "bvb"}, {"pzl", "hfx"}}
+	if got := g.HighlightedEdges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("toDOT() highlights %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,187 @@
+// Camel Cards hand ranking
+package camelcards
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HandType is a kind of hand, such as a full house, described by the sizes of its groups of equal cards
+type HandType struct {
+	Name string
+	// Groups are the sizes of the largest groups of equal cards a hand needs, largest first.
+	// A hand with larger or more groups still matches, so a full house {3, 2} is also a pair {2}.
+	Groups []int
+}
+
+// reachable returns whether a hand with groups of equal cards, largest first, is of the type once the wild cards are
+// added. Since larger groups still match, the wild cards are best spent growing the largest groups to the sizes the
+// type needs, starting new groups where the hand has too few.
+func (t HandType) reachable(groups []int, wilds int) bool {
+	needed := 0
+	for i, n := range t.Groups {
+		have := 0
+		if i < len(groups) {
+			have = groups[i]
+		}
+		needed += max(0, n-have)
+	}
+	return needed <= wilds
+}
+
+// StandardTypes are the hand types of Camel Cards from weakest to strongest
+var StandardTypes = []HandType{
+	{Name: "high card", Groups: []int{1}},
+	{Name: "one pair", Groups: []int{2}},
+	{Name: "two pair", Groups: []int{2, 2}},
+	{Name: "three of a kind", Groups: []int{3}},
+	{Name: "full house", Groups: []int{3, 2}},
+	{Name: "four of a kind", Groups: []int{4}},
+	{Name: "five of a kind", Groups: []int{5}},
+}
+
+// TieBreak is how hands of the same type are ordered
+type TieBreak int
+
+// Tie break policies
+const (
+	// Dealt compares the cards one by one in the order they were dealt
+	Dealt TieBreak = iota
+	// Sorted compares the cards one by one from the strongest card in each hand, as in poker
+	Sorted
+	// None leaves hands of the same type equal
+	None
+)
+
+// Rules are how hands are ranked
+type Rules struct {
+	// Order is every card from weakest to strongest
+	Order string
+	// Wild are the cards which act as whichever card makes the strongest hand type.
+	// They keep their place in Order for breaking ties.
+	Wild string
+	// Types are the hand types from weakest to strongest. A hand is the strongest type it matches.
+	Types []HandType
+	// Size is the number of cards in a hand, or 0 for any number
+	Size     int
+	TieBreak TieBreak
+}
+
+// Camel are the rules of the first part of the puzzle, where J is a jack
+var Camel = Rules{Order: "23456789TJQKA", Types: StandardTypes, Size: 5, TieBreak: Dealt}
+
+// Joker are the rules of the second part of the puzzle, where J is a joker and the weakest card
+var Joker = Rules{Order: "J23456789TQKA", Wild: "J", Types: StandardTypes, Size: 5, TieBreak: Dealt}
+
+// Validate returns an error if the rules cannot rank hands
+func (r Rules) Validate() error {
+	seen := map[rune]bool{}
+	for _, c := range r.Order {
+		if seen[c] {
+			return fmt.Errorf("card %q appears twice in the order %q", c, r.Order)
+		}
+		seen[c] = true
+	}
+	for _, c := range r.Wild {
+		if !seen[c] {
+			return fmt.Errorf("wild card %q is not in the order %q", c, r.Order)
+		}
+	}
+	if len(r.Types) == 0 {
+		return errors.New("rules have no hand types")
+	}
+	for _, t := range r.Types {
+		if !slices.IsSortedFunc(t.Groups, func(a, b int) int { return b - a }) || slices.Contains(t.Groups, 0) {
+			return fmt.Errorf("hand type %s has groups %v, want positive sizes largest first", t.Name, t.Groups)
+		}
+	}
+	if r.Size < 0 {
+		return fmt.Errorf("hand size %d is negative", r.Size)
+	}
+	if r.TieBreak < Dealt || r.TieBreak > None {
+		return fmt.Errorf("unknown tie break %d", r.TieBreak)
+	}
+	return nil
+}
+
+// Hand is a hand of cards ranked by a set of rules
+type Hand struct {
+	Cards string
+	// Type is the index of the hand type in the rules
+	Type int
+	// values are the strengths of the cards in the order they break ties
+	values []int
+}
+
+// Evaluate returns the ranked hand of cards under rules which have been checked with Validate.
+// An error is returned for a card not in the rules, a hand of the wrong size or a hand matching no type.
+func (r Rules) Evaluate(cards string) (Hand, error) {
+	h := Hand{Cards: cards}
+	counts := map[rune]int{}
+	wilds := 0
+	for _, c := range cards {
+		v := strings.IndexRune(r.Order, c)
+		if v < 0 {
+			return Hand{}, fmt.Errorf("hand %s has unknown card %q", cards, c)
+		}
+		h.values = append(h.values, v)
+		if strings.ContainsRune(r.Wild, c) {
+			wilds++
+		} else {
+			counts[c]++
+		}
+	}
+	if len(h.values) == 0 || r.Size > 0 && len(h.values) != r.Size {
+		return Hand{}, fmt.Errorf("hand %s has %d cards, want %d", cards, len(h.values), r.Size)
+	}
+
+	var groups []int
+	for _, n := range counts {
+		groups = append(groups, n)
+	}
+	h.Type = r.handType(groups, wilds)
+	if h.Type < 0 {
+		return Hand{}, fmt.Errorf("hand %s matches no hand type", cards)
+	}
+
+	switch r.TieBreak {
+	case Sorted:
+		slices.Sort(h.values)
+		slices.Reverse(h.values)
+	case None:
+		h.values = nil
+	}
+	return h, nil
+}
+
+// handType returns the index of the strongest type which groups of equal cards can make with the wild cards added,
+// or -1 if none can
+func (r Rules) handType(groups []int, wilds int) int {
+	slices.Sort(groups)
+	slices.Reverse(groups)
+	for i := len(r.Types) - 1; i >= 0; i-- {
+		if r.Types[i].reachable(groups, wilds) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Compare returns -1 if hand a is weaker than b, 1 if it is stronger and 0 if they are equal.
+// The hands must be evaluated by the same rules.
+func Compare(a, b Hand) int {
+	if a.Type != b.Type {
+		if a.Type < b.Type {
+			return -1
+		}
+		return 1
+	}
+	return slices.Compare(a.values, b.values)
+}
+
+// TypeName returns the name of the type of a hand evaluated by the rules
+func (r Rules) TypeName(h Hand) string {
+	return r.Types[h.Type].Name
+}
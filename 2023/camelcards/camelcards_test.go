@@ -0,0 +1,224 @@
+package camelcards
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		rules Rules
+		cards string
+		want  string
+	}{
+		{rules: Camel, cards: "32T3K", want: "one pair"},
+		{rules: Camel, cards: "KTJJT", want: "two pair"},
+		{rules: Camel, cards: "T55J5", want: "three of a kind"},
+		{rules: Camel, cards: "23332", want: "full house"},
+		{rules: Camel, cards: "AA8AA", want: "four of a kind"},
+		{rules: Camel, cards: "23456", want: "high card"},
+		{rules: Joker, cards: "KTJJT", want: "four of a kind"},
+		{rules: Joker, cards: "QQQJA", want: "four of a kind"},
+		{rules: Joker, cards: "JJJJJ", want: "five of a kind"},
+		{rules: Joker, cards: "2345J", want: "one pair"},
+		{rules: Joker, cards: "2J3J4", want: "three of a kind"},
+		{rules: Joker, cards: "22J33", want: "full house"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.cards, func(t *testing.T) {
+			h, err := tc.rules.Evaluate(tc.cards)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.rules.TypeName(h); got != tc.want {
+				t.Errorf("Evaluate(%s) is %s, want %s", tc.cards, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		rules        Rules
+		weak, strong string
+	}{
+		{rules: Camel, weak: "KTJJT", strong: "KK677"},
+		{rules: Camel, weak: "2AAAA", strong: "33332"},
+		{rules: Joker, weak: "JKKK2", strong: "QQQQ2"},
+		{rules: Joker, weak: "T55J5", strong: "QQQJA"},
+		// Sorted ties are broken by the strongest card first
+		{rules: Rules{Order: Camel.Order, Types: StandardTypes, TieBreak: Sorted}, weak: "2345A", strong: "AK234"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.weak+" "+tc.strong, func(t *testing.T) {
+			a, err := tc.rules.Evaluate(tc.weak)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := tc.rules.Evaluate(tc.strong)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Compare(a, b); got != -1 {
+				t.Errorf("Compare(%s, %s) = %d, want -1", tc.weak, tc.strong, got)
+			}
+			if got := Compare(b, a); got != 1 {
+				t.Errorf("Compare(%s, %s) = %d, want 1", tc.strong, tc.weak, got)
+			}
+		})
+	}
+
+	none := Rules{Order: Camel.Order, Types: StandardTypes, TieBreak: None}
+	a, _ := none.Evaluate("23456")
+	b, _ := none.Evaluate("789TA")
+	if got := Compare(a, b); got != 0 {
+		t.Errorf("Compare() of high cards without tie breaks = %d, want 0", got)
+	}
+}
+
+func TestVariants(t *testing.T) {
+	// Seven card hands with two kinds of wild card and a table where two pair beats three of a kind
+	rules := Rules{
+		Order: "*?23456789TJQKA",
+		Wild:  "*?",
+		Types: []HandType{
+			{Name: "high card", Groups: []int{1}},
+			{Name: "pair", Groups: []int{2}},
+			{Name: "three of a kind", Groups: []int{3}},
+			{Name: "two pair", Groups: []int{2, 2}},
+			{Name: "three pair", Groups: []int{2, 2, 2}},
+			{Name: "seven of a kind", Groups: []int{7}},
+		},
+		Size: 7,
+	}
+	if err := rules.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"23456*?": "two pair",
+		"22335*7": "three pair",
+		"2222222": "seven of a kind",
+		"?*???*?": "seven of a kind",
+		"2345678": "high card",
+	}
+	for cards, want := range tests {
+		h, err := rules.Evaluate(cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rules.TypeName(h); got != want {
+			t.Errorf("Evaluate(%s) is %s, want %s", cards, got, want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, cards := range []string{"", "2345", "234567", "2345X"} {
+		if _, err := Camel.Evaluate(cards); err == nil {
+			t.Errorf("Evaluate(%q) returned no error", cards)
+		}
+	}
+	noHighCard := Rules{Order: Camel.Order, Types: StandardTypes[1:]}
+	if _, err := noHighCard.Evaluate("23456"); err == nil {
+		t.Errorf("Evaluate() of a hand matching no type returned no error")
+	}
+
+	tests := map[string]Rules{
+		"repeated card":   {Order: "2234", Types: StandardTypes},
+		"unknown wild":    {Order: "234", Wild: "J", Types: StandardTypes},
+		"no types":        {Order: "234"},
+		"unsorted groups": {Order: "234", Types: []HandType{{Name: "odd", Groups: []int{2, 3}}}},
+		"tie break":       {Order: "234", Types: StandardTypes, TieBreak: None + 1},
+	}
+	for name, rules := range tests {
+		if err := rules.Validate(); err == nil {
+			t.Errorf("Validate() of rules with a %s returned no error", name)
+		}
+	}
+}
+
+// TestLargeHand checks that hands with many wild cards are evaluated without trying every way of placing them
+func TestLargeHand(t *testing.T) {
+	rules := Rules{
+		Order: Joker.Order,
+		Wild:  "J",
+		Types: []HandType{
+			{Name: "anything", Groups: []int{1}},
+			{Name: "three tens", Groups: []int{10, 10, 10}},
+			{Name: "four tens", Groups: []int{10, 10, 10, 10}},
+		},
+	}
+	if err := rules.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		cards string
+		want  string
+	}{
+		// 10 jokers fill the two missing groups of 5 to make 3 groups of 10
+		{cards: strings.Repeat("A", 10) + strings.Repeat("K", 5) + strings.Repeat("Q", 5) + strings.Repeat("J", 10), want: "three tens"},
+		{cards: strings.Repeat("A", 10) + strings.Repeat("K", 5) + strings.Repeat("Q", 5) + strings.Repeat("J", 9), want: "anything"},
+		{cards: strings.Repeat("J", 40), want: "four tens"},
+		{cards: "23456789TQKA" + strings.Repeat("J", 28), want: "three tens"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			h, err := rules.Evaluate(tc.cards)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rules.TypeName(h); got != tc.want {
+				t.Errorf("Evaluate(%s) is %s, want %s", tc.cards, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestWildsBruteForce checks that wild cards make the same hand type as trying every card in their place
+func TestWildsBruteForce(t *testing.T) {
+	plain := Rules{Order: Joker.Order, Types: StandardTypes, Size: 5}
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 2000; i++ {
+		var sb strings.Builder
+		for j := 0; j < 5; j++ {
+			// Make jokers common so that hands with several are tried
+			if r.Intn(3) == 0 {
+				sb.WriteByte('J')
+			} else {
+				sb.WriteByte(Joker.Order[1+r.Intn(4)])
+			}
+		}
+		cards := sb.String()
+
+		want := -1
+		var try func(prefix, rest string)
+		try = func(prefix, rest string) {
+			if rest == "" {
+				h, err := plain.Evaluate(prefix)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want = max(want, h.Type)
+				return
+			}
+			if rest[0] != 'J' {
+				try(prefix+rest[:1], rest[1:])
+				return
+			}
+			// A wild card only needs to copy a card of the hand or be a card not in it
+			for _, c := range Joker.Order[1:6] {
+				try(prefix+string(c), rest[1:])
+			}
+		}
+		try("", cards)
+
+		h, err := Joker.Evaluate(cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Type != want {
+			t.Errorf("Evaluate(%s) is %s, want %s", cards, Joker.TypeName(h), StandardTypes[want].Name)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 func parseData(data []byte) ([][2]int, [][]int, error) {
@@ -66,6 +68,15 @@ func buildDependencyGraph(rules [][2]int) dependencyGraph {
 	return m
 }
 
+// toDOT returns the page ordering rules as a graph from each page to the pages which must come after it.
+func toDOT(rules [][2]int) *dot.Graph {
+	g := dot.New("rules", true)
+	for _, r := range rules {
+		g.Edge(strconv.Itoa(r[0]), strconv.Itoa(r[1]), nil)
+	}
+	return g
+}
+
 func (g dependencyGraph) isValid(pages []int) bool {
 	for i, page := range pages {
 		if _, ok := g[page]; !ok {
@@ -121,6 +132,11 @@ func Run(inputFile string) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(rules)); err != nil {
+			log.Fatal(err)
+		}
+	}
 	graph := buildDependencyGraph(rules)
 
 	p1 := part1(graph, pageNumbers)
@@ -58,3 +58,17 @@ func TestPart2(t *testing.T) {
 		t.Errorf("part2(%v, %v) = %d, want %d", graph, pageNumbers, got, want)
 	}
 }
+
+func TestToDOT(t *testing.T) {
+	rules, _, err := parseData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := toDOT(rules)
+	if got := len(g.Nodes()); got != 7 {
+		t.Errorf("toDOT() has %d nodes, want 7", got)
+	}
+	if got := g.Edges(); got != len(rules) {
+		t.Errorf("toDOT() has %d edges, want %d", got, len(rules))
+	}
+}
@@ -2,6 +2,7 @@
 package day9
 
 import (
+	"container/heap"
 	"fmt"
 	"log"
 	"os"
@@ -9,110 +10,128 @@ import (
 	"strings"
 )
 
-func parseData(data []byte) ([]int, error) {
+// span is a run of blocks on the disk holding part or all of a file, or free space
+type span struct {
+	id, pos, length int
+}
+
+// checksum returns the sum of the positions of the blocks of the span multiplied by its file ID
+func (s span) checksum() int {
+	return s.id * (s.length*s.pos + s.length*(s.length-1)/2)
+}
+
+// diskMap is the files and free space on the disk, each in order of position
+type diskMap struct {
+	files, free []span
+}
+
+func parseData(data []byte) (diskMap, error) {
 	s := strings.TrimSuffix(string(data), "\n")
-	var arr []int
+	var d diskMap
 	var isFree bool
-	var id int
+	var pos int
 	for _, r := range s {
 		n, err := strconv.Atoi(string(r))
 		if err != nil {
-			return nil, err
-		}
-		for i := 0; i < n; i++ {
-			if isFree {
-				arr = append(arr, -1)
-			} else {
-				arr = append(arr, id)
-			}
+			return diskMap{}, err
 		}
 		if isFree {
-			id += 1
+			if n > 0 {
+				d.free = append(d.free, span{id: -1, pos: pos, length: n})
+			}
+		} else {
+			d.files = append(d.files, span{id: len(d.files), pos: pos, length: n})
 		}
+		pos += n
 		isFree = !isFree
 	}
-	return arr, nil
+	return d, nil
 }
 
-func part1(arr []int) int {
-	l := 0
-	r := len(arr) - 1
-	for l < r {
-		if arr[l] >= 0 {
-			l++
-			continue
-		}
-		if arr[r] < 0 {
-			r--
-			continue
+func part1(d diskMap) int {
+	files := make([]span, len(d.files))
+	copy(files, d.files)
+	var moved []span
+	for _, free := range d.free {
+		// Fill the free span with blocks from the end of the last file while it is to the right
+		for free.length > 0 && len(files) > 0 && files[len(files)-1].pos > free.pos {
+			last := &files[len(files)-1]
+			n := min(free.length, last.length)
+			moved = append(moved, span{id: last.id, pos: free.pos, length: n})
+			free.pos += n
+			free.length -= n
+			last.length -= n
+			if last.length == 0 {
+				files = files[:len(files)-1]
+			}
 		}
-		arr[l] = arr[r]
-		arr[r] = -1
-		r--
 	}
-	return checksum(arr)
+	return checksum(files) + checksum(moved)
 }
 
-func part2(arr []int) int {
-	// Last element is part of a file. The first file never needs to move.
-	for fileID := arr[len(arr)-1]; fileID > 0; fileID-- {
-		leftPos, rightPos := findFile(arr, fileID)
-		length := rightPos - leftPos + 1
-		pos, ok := findFreeSpace(arr[:leftPos], length)
-		if ok {
-			for i := leftPos; i <= rightPos; i++ {
-				arr[i] = -1
+func part2(d diskMap) int {
+	// Free spans are kept in a heap of positions for each length, so the leftmost span that a file fits in is the
+	// earliest of the heaps for lengths at least as long as the file
+	var free [10]positions
+	for _, s := range d.free {
+		free[s.length] = append(free[s.length], s.pos)
+	}
+	for i := range free {
+		heap.Init(&free[i])
+	}
+
+	files := make([]span, len(d.files))
+	copy(files, d.files)
+	for i := len(files) - 1; i > 0; i-- {
+		f := &files[i]
+		best := -1
+		for length := f.length; length < len(free); length++ {
+			if len(free[length]) == 0 || free[length][0] > f.pos {
+				continue
 			}
-			for i := pos; i < pos+length; i++ {
-				arr[i] = fileID
+			if best < 0 || free[length][0] < free[best][0] {
+				best = length
 			}
 		}
+		if best < 0 {
+			continue
+		}
+		f.pos = heap.Pop(&free[best]).(int)
+		// The space the file leaves is to the right of every file still to move, so it is never used again
+		if rest := best - f.length; rest > 0 {
+			heap.Push(&free[rest], f.pos+f.length)
+		}
 	}
-	return checksum(arr)
+	return checksum(files)
 }
 
-func checksum(arr []int) int {
+func checksum(spans []span) int {
 	var sum int
-	for i, n := range arr {
-		if n < 0 {
-			continue
-		}
-		sum += i * n
+	for _, s := range spans {
+		sum += s.checksum()
 	}
 	return sum
 }
 
-func findFile(arr []int, id int) (int, int) {
-	var leftPos int
-	for i := 0; i < len(arr); i++ {
-		if arr[i] == id {
-			leftPos = i
-			break
-		}
-	}
-	var rightPos int
-	for i := len(arr) - 1; i >= 0; i-- {
-		if arr[i] == id {
-			rightPos = i
-			break
-		}
-	}
-	return leftPos, rightPos
+// positions is a min-heap of the positions of free spans
+type positions []int
+
+func (p positions) Len() int { return len(p) }
+
+func (p positions) Less(i, j int) bool { return p[i] < p[j] }
+
+func (p positions) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (p *positions) Push(x any) {
+	*p = append(*p, x.(int))
 }
 
-func findFreeSpace(arr []int, l int) (int, bool) {
-	var currentFree int
-	for i, n := range arr {
-		if n >= 0 {
-			currentFree = 0
-			continue
-		}
-		currentFree += 1
-		if currentFree == l {
-			return i - currentFree + 1, true
-		}
-	}
-	return 0, false
+func (p *positions) Pop() any {
+	old := *p
+	n := len(old)
+	x := old[n-1]
+	*p = old[:n-1]
+	return x
 }
 
 func Run(inputFile string) {
@@ -120,17 +139,14 @@ func Run(inputFile string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	arr, err := parseData(data)
+	d, err := parseData(data)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	arr2 := make([]int, len(arr))
-	copy(arr2, arr)
-
-	p1 := part1(arr)
+	p1 := part1(d)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(arr2)
+	p2 := part2(d)
 	fmt.Println("Part 2:", p2)
 }
@@ -1,6 +1,7 @@
 package day9
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -29,3 +30,117 @@ func TestPart2(t *testing.T) {
 		t.Errorf("part2(%s) = %d, want %d", input, got, want)
 	}
 }
+
+// expand returns the disk map as one entry per block holding the file ID, or -1 for free space
+func expand(data []byte) []int {
+	var blocks []int
+	for i, r := range data {
+		id := -1
+		if i%2 == 0 {
+			id = i / 2
+		}
+		for n := int(r - '0'); n > 0; n-- {
+			blocks = append(blocks, id)
+		}
+	}
+	return blocks
+}
+
+// compactBlocks moves single blocks or whole files into the leftmost free space, one block at a time, as a reference
+// for the span based compaction
+func compactBlocks(blocks []int, wholeFiles bool) int {
+	if !wholeFiles {
+		for l, r := 0, len(blocks)-1; l < r; {
+			switch {
+			case blocks[l] >= 0:
+				l++
+			case blocks[r] < 0:
+				r--
+			default:
+				blocks[l], blocks[r] = blocks[r], -1
+			}
+		}
+	} else {
+		for r := len(blocks) - 1; r > 0; {
+			if blocks[r] < 0 {
+				r--
+				continue
+			}
+			end := r
+			for r >= 0 && blocks[r] == blocks[end] {
+				r--
+			}
+			length := end - r
+			for l, run := 0, 0; l <= r; l++ {
+				if blocks[l] >= 0 {
+					run = 0
+					continue
+				}
+				if run++; run == length {
+					for i := 0; i < length; i++ {
+						blocks[l-i], blocks[end-i] = blocks[end-i], -1
+					}
+					break
+				}
+			}
+		}
+	}
+	var sum int
+	for i, id := range blocks {
+		if id > 0 {
+			sum += i * id
+		}
+	}
+	return sum
+}
+
+// generate returns a random disk map with a number of files
+func generate(r *rand.Rand, files int) []byte {
+	data := make([]byte, 2*files-1)
+	for i := range data {
+		data[i] = byte('0' + r.Intn(10))
+		if i%2 == 0 && data[i] == '0' {
+			data[i] = '1'
+		}
+	}
+	return data
+}
+
+func TestMatchesBlocks(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for i := 0; i < 500; i++ {
+		data := generate(r, 1+r.Intn(40))
+		d, err := parseData(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := part1(d), compactBlocks(expand(data), false); got != want {
+			t.Errorf("part1(%s) = %d, want %d", data, got, want)
+		}
+		if got, want := part2(d), compactBlocks(expand(data), true); got != want {
+			t.Errorf("part2(%s) = %d, want %d", data, got, want)
+		}
+	}
+}
+
+func BenchmarkPart1(b *testing.B) {
+	d, err := parseData(generate(rand.New(rand.NewSource(9)), 500000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		part1(d)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	d, err := parseData(generate(rand.New(rand.NewSource(9)), 500000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		part2(d)
+	}
+}
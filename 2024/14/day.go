@@ -7,7 +7,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/maze-mapper/advent-of-code/animate"
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/ocr"
 )
 
 func parseData(data []byte) ([]robot, error) {
@@ -61,6 +63,9 @@ func part2(robots []robot, xSize, ySize int) int {
 			positions[r.position] += 1
 			newRobots[i] = r
 		}
+		if animate.Enabled() {
+			animate.Frame(renderPositions(positions, xSize, ySize))
+		}
 		if hasSquare(positions) {
 			printPositions(positions, xSize, ySize)
 			break
@@ -94,19 +99,27 @@ func hasSquare(positions map[coordinates.Coord]int) bool {
 	return false
 }
 
+// renderPositions returns the area with a '#' for each position with a robot.
+func renderPositions(positions map[coordinates.Coord]int, xSize, ySize int) [][]rune {
+	grid := make([][]rune, ySize)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(".", xSize))
+	}
+	for p := range positions {
+		grid[p.Y][p.X] = '#'
+	}
+	return grid
+}
+
 func printPositions(positions map[coordinates.Coord]int, xSize, ySize int) {
-	var b strings.Builder
-	for y := 0; y < ySize; y++ {
-		for x := 0; x < xSize; x++ {
-			if _, ok := positions[coordinates.Coord{X: x, Y: y}]; ok {
-				b.WriteString("#")
-			} else {
-				b.WriteString(".")
-			}
-		}
-		b.WriteString("\n")
+	grid := make([][]bool, ySize)
+	for y := range grid {
+		grid[y] = make([]bool, xSize)
+	}
+	for p := range positions {
+		grid[p.Y][p.X] = true
 	}
-	fmt.Println(b.String())
+	fmt.Println(ocr.Render(grid))
 }
 
 func wrap(position coordinates.Coord, xSize, ySize int) coordinates.Coord {
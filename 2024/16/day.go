@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 func parseData(data []byte) ([][]bool, coordinates.Coord, coordinates.Coord) {
@@ -32,7 +34,7 @@ func parseData(data []byte) ([][]bool, coordinates.Coord, coordinates.Coord) {
 	return maze, start, end
 }
 
-func part1(maze [][]bool, start, end coordinates.Coord) int {
+func part1(maze [][]bool, start, end coordinates.Coord) (int, []coordinates.Coord) {
 	pq := make(PriorityQueue, 1)
 	pq[0] = &Item{
 		state: gameState{
@@ -45,18 +47,21 @@ func part1(maze [][]bool, start, end coordinates.Coord) int {
 	heap.Init(&pq)
 
 	visited := map[string]int{}
+	// reached holds the state each key was best reached in, which links to the state before it.
+	reached := map[string]gameState{}
 
 	for pq.Len() > 0 {
 		item := heap.Pop(&pq).(*Item)
 
 		if item.state.position == end {
-			return item.state.score
+			return item.state.score, item.state.pathFrom(reached)
 		}
 
 		if s, ok := visited[item.state.key()]; ok && s <= item.state.score {
 			continue
 		}
 		visited[item.state.key()] = item.state.score
+		reached[item.state.key()] = item.state
 
 		// Next moves.
 		// Turn left or right.
@@ -65,11 +70,13 @@ func part1(maze [][]bool, start, end coordinates.Coord) int {
 				position: item.state.position,
 				facing:   (item.state.facing + 1) % 4,
 				score:    item.state.score + 1000,
+				from:     item.state.key(),
 			},
 			{
 				position: item.state.position,
 				facing:   (item.state.facing + 3) % 4,
 				score:    item.state.score + 1000,
+				from:     item.state.key(),
 			},
 		}
 		// Move in the facing direction.
@@ -89,6 +96,7 @@ func part1(maze [][]bool, start, end coordinates.Coord) int {
 				position: c,
 				facing:   item.state.facing,
 				score:    item.state.score + 1,
+				from:     item.state.key(),
 			})
 		}
 
@@ -102,10 +110,10 @@ func part1(maze [][]bool, start, end coordinates.Coord) int {
 			})
 		}
 	}
-	return 0
+	return 0, nil
 }
 
-func part2(maze [][]bool, start, end coordinates.Coord) int {
+func part2(maze [][]bool, start, end coordinates.Coord) (int, map[coordinates.Coord]bool) {
 	pq := make(PriorityQueue, 1)
 	pq[0] = &Item{
 		state: gameState{
@@ -196,7 +204,27 @@ func part2(maze [][]bool, start, end coordinates.Coord) int {
 			})
 		}
 	}
-	return len(bestPathTiles)
+	return len(bestPathTiles), bestPathTiles
+}
+
+// drawPath draws marks over the walls of the maze.
+func drawPath(label string, maze [][]bool, start, end coordinates.Coord, marks map[coordinates.Coord]rune) {
+	grid := make([][]rune, len(maze))
+	for i, row := range maze {
+		grid[i] = make([]rune, len(row))
+		for j, isWall := range row {
+			if isWall {
+				grid[i][j] = '#'
+			} else {
+				grid[i][j] = '.'
+			}
+		}
+	}
+	grid[start.Y][start.X] = 'S'
+	grid[end.Y][end.X] = 'E'
+	if err := overlay.Draw(label, grid, marks); err != nil {
+		log.Fatal(err)
+	}
 }
 
 type direction int
@@ -213,12 +241,28 @@ type gameState struct {
 	facing   direction
 	score    int
 	path     []coordinates.Coord
+	// from is the key of the state this state was reached from, empty for the start.
+	from string
 }
 
 func (s gameState) key() string {
 	return fmt.Sprintf("%d-%d-%d", s.position.X, s.position.Y, s.facing)
 }
 
+// pathFrom returns the positions from the start to this state by following the states it was reached from.
+// Turning on the spot does not add a position.
+func (s gameState) pathFrom(reached map[string]gameState) []coordinates.Coord {
+	path := []coordinates.Coord{s.position}
+	for s.from != "" {
+		s = reached[s.from]
+		if s.position != path[len(path)-1] {
+			path = append(path, s.position)
+		}
+	}
+	slices.Reverse(path)
+	return path
+}
+
 // An Item is something we manage in a priority queue.
 type Item struct {
 	state    gameState
@@ -267,9 +311,15 @@ func Run(inputFile string) {
 	}
 	maze, start, end := parseData(data)
 
-	p1 := part1(maze, start, end)
+	p1, path := part1(maze, start, end)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(maze, start, end)
+	p2, tiles := part2(maze, start, end)
 	fmt.Println("Part 2:", p2)
+
+	if overlay.Enabled() {
+		drawPath("part1", maze, start, end, overlay.Arrows(path))
+		// Every tile on any of the best paths.
+		drawPath("part2", maze, start, end, overlay.Tiles(tiles, 'O'))
+	}
 }
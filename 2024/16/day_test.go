@@ -2,6 +2,8 @@ package day16
 
 import (
 	"testing"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
 )
 
 var input1 = []byte(`###############
@@ -58,7 +60,7 @@ func TestPart1(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(string(tc.name), func(t *testing.T) {
 			maze, start, end := parseData(tc.input)
-			got := part1(maze, start, end)
+			got, _ := part1(maze, start, end)
 			if got != tc.want {
 				t.Errorf("part1(%s) = %d, want %d", tc.input, got, tc.want)
 			}
@@ -66,6 +68,27 @@ func TestPart1(t *testing.T) {
 	}
 }
 
+func TestPart1Path(t *testing.T) {
+	for name, input := range map[string][]byte{"Example_1": input1, "Example_2": input2} {
+		t.Run(name, func(t *testing.T) {
+			maze, start, end := parseData(input)
+			score, path := part1(maze, start, end)
+			if path[0] != start || path[len(path)-1] != end {
+				t.Fatalf("path runs from %v to %v, want %v to %v", path[0], path[len(path)-1], start, end)
+			}
+			for i := 1; i < len(path); i++ {
+				if coordinates.ManhattanDistance(path[i-1], path[i]) != 1 || maze[path[i].Y][path[i].X] {
+					t.Fatalf("path steps from %v to %v", path[i-1], path[i])
+				}
+			}
+			// Every move scores 1 and every turn 1000
+			if moves := len(path) - 1; moves != score%1000 {
+				t.Errorf("path has %d moves, want %d", moves, score%1000)
+			}
+		})
+	}
+}
+
 func TestPart2(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -86,7 +109,7 @@ func TestPart2(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(string(tc.name), func(t *testing.T) {
 			maze, start, end := parseData(tc.input)
-			got := part2(maze, start, end)
+			got, _ := part2(maze, start, end)
 			if got != tc.want {
 				t.Errorf("part2(%s) = %d, want %d", tc.input, got, tc.want)
 			}
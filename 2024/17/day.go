@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/machine"
 )
 
 func parseData(data []byte) ([3]int, []int, error) {
@@ -38,119 +41,250 @@ func part1(registers [3]int, program []int) string {
 	if err != nil {
 		log.Fatal(err)
 	}
-	return out
+	output := make([]string, len(out))
+	for i, o := range out {
+		output[i] = strconv.Itoa(o)
+	}
+	return strings.Join(output, ",")
 }
 
-func part2(registers [3]int, program []int) int {
-	a, _ := findInitialARegister(program, len(program)-1, 0, 8)
-	return a
+func part2(registers [3]int, program []int) (int, error) {
+	if err := checkLoopShape(program); err != nil {
+		return 0, err
+	}
+	a, found, err := findInitialARegister(registers, program, len(program)-1, 0)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("no initial value for the A register outputs the program")
+	}
+	return a, nil
+}
+
+// shift returns the A register divided by two to the power of the combo operand
+func shift(m *machine.Machine, operand int) (int, error) {
+	operand, err := combo(m.Registers, operand)
+	if err != nil {
+		return 0, err
+	}
+	return m.Registers[0] >> operand, nil
+}
+
+// instructionSet holds the instructions of the 3-bit computer, which are listed in order of their opcodes
+var instructionSet = machine.MustNewSet(
+	[]string{"A", "B", "C"},
+	machine.Op{
+		Name:     "adv",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			v, err := shift(m, args[0])
+			m.Registers[0] = v
+			return err
+		},
+	},
+	machine.Op{
+		Name:     "bxl",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Registers[1] ^= args[0]
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "bst",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			operand, err := combo(m.Registers, args[0])
+			m.Registers[1] = operand % 8
+			return err
+		},
+	},
+	machine.Op{
+		Name:     "jnz",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			if m.Registers[0] == 0 {
+				return nil
+			}
+			// Instructions are two values long so the jump target is halved to index the instructions
+			if args[0]%2 != 0 {
+				return fmt.Errorf("jump to %d is not the start of an instruction", args[0])
+			}
+			m.Goto(args[0] / 2)
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "bxc",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			m.Registers[1] ^= m.Registers[2]
+			return nil
+		},
+	},
+	machine.Op{
+		Name:     "out",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			operand, err := combo(m.Registers, args[0])
+			m.Emit(operand % 8)
+			return err
+		},
+	},
+	machine.Op{
+		Name:     "bdv",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			v, err := shift(m, args[0])
+			m.Registers[1] = v
+			return err
+		},
+	},
+	machine.Op{
+		Name:     "cdv",
+		Operands: []machine.OperandKind{machine.Immediate},
+		Exec: func(m *machine.Machine, args []int) error {
+			v, err := shift(m, args[0])
+			m.Registers[2] = v
+			return err
+		},
+	},
+)
+
+// opcodeNames are the names of the instructions indexed by opcode
+var opcodeNames = []string{"adv", "bxl", "bst", "jnz", "bxc", "out", "bdv", "cdv"}
+
+// decodeProgram converts pairs of opcode and operand in to instructions
+func decodeProgram(program []int) ([]machine.Instruction, error) {
+	if len(program)%2 != 0 {
+		return nil, fmt.Errorf("program has an odd number of values")
+	}
+	instructions := make([]machine.Instruction, len(program)/2)
+	for i := range instructions {
+		opcode, operand := program[2*i], program[2*i+1]
+		if opcode < 0 || opcode >= len(opcodeNames) {
+			return nil, fmt.Errorf("opcode %d is not valid", opcode)
+		}
+		inst, err := instructionSet.Instruction(opcodeNames[opcode], operand)
+		if err != nil {
+			return nil, err
+		}
+		instructions[i] = inst
+	}
+	return instructions, nil
 }
 
 // runProgram returns the program output for the given initial register values.
-func runProgram(registers [3]int, program []int) (string, error) {
-	var output []string
-	for idx := 0; idx < len(program)-1; idx += 2 {
-		operator := program[idx]
-		operand := program[idx+1]
+func runProgram(registers [3]int, program []int) ([]int, error) {
+	instructions, err := decodeProgram(program)
+	if err != nil {
+		return nil, err
+	}
+	m := machine.New(instructionSet, instructions)
+	copy(m.Registers, registers[:])
+	if err := m.Run(); err != nil {
+		return nil, err
+	}
+	return m.Output, nil
+}
 
-		switch operator {
+// checkLoopShape checks that the program is a single loop which outputs one value and shifts the A register right by
+// three bits on each iteration, and that the B and C registers are set from A before being used in each iteration.
+// This shape means the last values output only depend on the highest bits of the initial A register.
+func checkLoopShape(program []int) error {
+	if len(program)%2 != 0 {
+		return fmt.Errorf("program has an odd number of values")
+	}
+	n := len(program)
+	if n < 2 || program[n-2] != 3 || program[n-1] != 0 {
+		return fmt.Errorf("program does not end with a jump to the start")
+	}
 
+	var shifts, outputs int
+	written := map[int]bool{}
+	// read reports an error if a combo operand refers to the B or C register before it is set in this iteration
+	read := func(idx, operand int) error {
+		if (operand == 5 && !written[1]) || (operand == 6 && !written[2]) {
+			return fmt.Errorf("instruction %d reads a register carried over from the previous iteration", idx)
+		}
+		return nil
+	}
+	for idx := 0; idx < n-2; idx += 2 {
+		operator, operand := program[idx], program[idx+1]
+		var err error
+		switch operator {
 		case 0: // adv
-			operand, err := combo(registers, operand)
-			if err != nil {
-				return "", err
-			}
-			denominator := 1
-			for i := 1; i <= operand; i++ {
-				denominator *= 2
+			if operand != 3 {
+				return fmt.Errorf("instruction %d shifts the A register by combo operand %d, not 3", idx, operand)
 			}
-			registers[0] /= denominator
-
+			shifts++
 		case 1: // bxl
-			registers[1] ^= operand
-
+			err = read(idx, 5)
 		case 2: // bst
-			operand, err := combo(registers, operand)
-			if err != nil {
-				return "", err
-			}
-			registers[1] = operand % 8
-
+			err = read(idx, operand)
+			written[1] = true
 		case 3: // jnz
-			if registers[0] != 0 {
-				idx = operand
-				idx -= 2 // So the usual +2 doesn't happen.
-			}
-
+			return fmt.Errorf("instruction %d jumps within the loop", idx)
 		case 4: // bxc
-			registers[1] = registers[1] ^ registers[2]
-
-		case 5: // out
-			operand, err := combo(registers, operand)
-			if err != nil {
-				return "", err
+			if err = read(idx, 5); err == nil {
+				err = read(idx, 6)
 			}
-			output = append(output, strconv.Itoa(operand%8))
-
+		case 5: // out
+			err = read(idx, operand)
+			outputs++
 		case 6: // bdv
-			operand, err := combo(registers, operand)
-			if err != nil {
-				return "", err
-			}
-			denominator := 1
-			for i := 1; i <= operand; i++ {
-				denominator *= 2
-			}
-			registers[1] = registers[0] / denominator
-
+			err = read(idx, operand)
+			written[1] = true
 		case 7: // cdv
-			operand, err := combo(registers, operand)
-			if err != nil {
-				return "", err
-			}
-			denominator := 1
-			for i := 1; i <= operand; i++ {
-				denominator *= 2
-			}
-			registers[2] = registers[0] / denominator
+			err = read(idx, operand)
+			written[2] = true
+		}
+		if err != nil {
+			return err
 		}
 	}
-	return strings.Join(output, ","), nil
+	if shifts != 1 {
+		return fmt.Errorf("program shifts the A register %d times per loop, not once", shifts)
+	}
+	if outputs != 1 {
+		return fmt.Errorf("program outputs %d values per loop, not one", outputs)
+	}
+	return nil
 }
 
 // findInitialARegister works backwards through every value output by the
 // program to find the initial value of the A register such that the output is
 // the program itself.
-// Lower is the inclusive minimum value for the A register.
-// Upper is the exclusive maximum value for the A register.
-func findInitialARegister(program []int, i, lower, upper int) (int, bool) {
-	want := program[i]
-	for a := lower; a < upper; a++ {
-		if reverseARegister(a) == want {
-			if i == 0 {
-				return a, true
-			}
-			ans, found := findInitialARegister(program, i-1, a*8, (a+1)*8)
-			if found {
-				return ans, true
-			}
+// Each loop consumes three bits of the A register so the three bit chunks are
+// chosen from the highest down, running the program on each candidate to check
+// that it outputs the tail of the program from index i.
+func findInitialARegister(registers [3]int, program []int, i, prefix int) (int, bool, error) {
+	for chunk := 0; chunk < 8; chunk++ {
+		a := prefix*8 + chunk
+		if a == 0 {
+			continue
+		}
+		registers[0] = a
+		out, err := runProgram(registers, program)
+		if err != nil {
+			return 0, false, err
+		}
+		if !slices.Equal(out, program[i:]) {
+			continue
+		}
+		if i == 0 {
+			return a, true, nil
+		}
+		ans, found, err := findInitialARegister(registers, program, i-1, a)
+		if err != nil || found {
+			return ans, found, err
 		}
 	}
-	return 0, false
-}
-
-// reverseARegister undoes the effect of one loop of the program on the A register value.
-// This function is dependent on the program and will not work for all inputs.
-func reverseARegister(a int) int {
-	k := (a % 8) ^ 5
-	denominator := 1
-	for i := 1; i <= k; i++ {
-		denominator *= 2
-	}
-	return (k ^ 6 ^ (a / denominator)) % 8
+	return 0, false, nil
 }
 
-func combo(registers [3]int, operand int) (int, error) {
+func combo(registers []int, operand int) (int, error) {
 	switch operand {
 	case 0, 1, 2, 3:
 		return operand, nil
@@ -178,6 +312,9 @@ func Run(inputFile string) {
 	p1 := part1(registers, program)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(registers, program)
+	p2, err := part2(registers, program)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 2:", p2)
 }
@@ -28,14 +28,44 @@ func TestPart1(t *testing.T) {
 	}
 }
 
-// func TestPart2(t *testing.T) {
-// 	want := 117440
-// 	registers, program, err := parseData(input2)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// 	got := part2(registers, program)
-// 	if got != want {
-// 		t.Errorf("part2(%s) = %d, want %d", input1, got, want)
-// 	}
-// }
+func TestPart2(t *testing.T) {
+	want := 117440
+	registers, program, err := parseData(input2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := part2(registers, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("part2(%s) = %d, want %d", input2, got, want)
+	}
+}
+
+func TestPart2LoopShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []int
+	}{
+		{name: "shift by one", program: []int{0, 1, 5, 4, 3, 0}},
+		{name: "no jump", program: []int{0, 3, 5, 4}},
+		{name: "two outputs", program: []int{0, 3, 5, 4, 5, 4, 3, 0}},
+		{name: "carried register", program: []int{1, 2, 0, 3, 5, 5, 3, 0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := part2([3]int{}, tc.program); err == nil {
+				t.Errorf("part2(%v) returned no error", tc.program)
+			}
+		})
+	}
+}
+
+func TestRunProgramOddJump(t *testing.T) {
+	// The jump lands on the operand of the first instruction
+	program := []int{0, 3, 3, 1}
+	if out, err := runProgram([3]int{8, 0, 0}, program); err == nil {
+		t.Errorf("runProgram(%v) = %v, want an error", program, out)
+	}
+}
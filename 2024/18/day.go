@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/maze-mapper/advent-of-code/coordinates"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 func parseData(data []byte) (map[coordinates.Coord]int, error) {
@@ -33,7 +35,7 @@ func parseData(data []byte) (map[coordinates.Coord]int, error) {
 	return m, nil
 }
 
-func part1(fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int) int {
+func part1(fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int) (int, []coordinates.Coord) {
 	start := coordinates.Coord{X: 0, Y: 0}
 	end := coordinates.Coord{X: xMax, Y: yMax}
 
@@ -42,40 +44,56 @@ func part1(fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int)
 		state: gameState{
 			position: start,
 			steps:    0,
+			from:     start,
 		},
 		priority: 0,
 	}
 	heap.Init(&pq)
 
 	visited := map[coordinates.Coord]int{}
+	// previous holds the position each position was best reached from.
+	previous := map[coordinates.Coord]coordinates.Coord{}
 	for pq.Len() > 0 {
 		item := heap.Pop(&pq).(*Item)
 
 		if item.state.position == end {
-			return item.state.steps
+			path := []coordinates.Coord{end}
+			for c := item.state.from; c != start; c = previous[c] {
+				path = append(path, c)
+			}
+			if end != start {
+				path = append(path, start)
+			}
+			slices.Reverse(path)
+			return item.state.steps, path
 		}
 
 		if s, ok := visited[item.state.position]; ok && s <= item.state.steps {
 			continue
 		}
 		visited[item.state.position] = item.state.steps
+		previous[item.state.position] = item.state.from
 
 		nextStates := []gameState{
 			{
 				position: coordinates.Coord{X: item.state.position.X + 1, Y: item.state.position.Y},
 				steps:    item.state.steps + 1,
+				from:     item.state.position,
 			},
 			{
 				position: coordinates.Coord{X: item.state.position.X - 1, Y: item.state.position.Y},
 				steps:    item.state.steps + 1,
+				from:     item.state.position,
 			},
 			{
 				position: coordinates.Coord{X: item.state.position.X, Y: item.state.position.Y + 1},
 				steps:    item.state.steps + 1,
+				from:     item.state.position,
 			},
 			{
 				position: coordinates.Coord{X: item.state.position.X, Y: item.state.position.Y - 1},
 				steps:    item.state.steps + 1,
+				from:     item.state.position,
 			},
 		}
 
@@ -99,12 +117,12 @@ func part1(fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int)
 		}
 	}
 
-	return 0
+	return 0, nil
 }
 
 func part2(fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int) string {
 	for b := fallenBytes + 1; b <= len(fallingBytes); b++ {
-		if steps := part1(fallingBytes, xMax, yMax, b); steps == 0 {
+		if steps, _ := part1(fallingBytes, xMax, yMax, b); steps == 0 {
 			for k, v := range fallingBytes {
 				if v == b {
 					return fmt.Sprintf("%d,%d", k.X, k.Y)
@@ -118,6 +136,26 @@ func part2(fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int)
 type gameState struct {
 	position coordinates.Coord
 	steps    int
+	// from is the position moved from to reach this state.
+	from coordinates.Coord
+}
+
+// drawPath draws the path over the memory space with the first fallenBytes bytes fallen.
+func drawPath(label string, fallingBytes map[coordinates.Coord]int, xMax, yMax, fallenBytes int, path []coordinates.Coord) {
+	grid := make([][]rune, yMax+1)
+	for y := range grid {
+		grid[y] = make([]rune, xMax+1)
+		for x := range grid[y] {
+			if t, ok := fallingBytes[coordinates.Coord{X: x, Y: y}]; ok && t <= fallenBytes {
+				grid[y][x] = '#'
+			} else {
+				grid[y][x] = '.'
+			}
+		}
+	}
+	if err := overlay.Draw(label, grid, overlay.Arrows(path)); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // An Item is something we manage in a priority queue.
@@ -175,8 +213,11 @@ func Run(inputFile string) {
 	yMax := 70
 	fallenBytes := 1024
 
-	p1 := part1(fallingBytes, xMax, yMax, fallenBytes)
+	p1, path := part1(fallingBytes, xMax, yMax, fallenBytes)
 	fmt.Println("Part 1:", p1)
+	if overlay.Enabled() {
+		drawPath("part1", fallingBytes, xMax, yMax, fallenBytes, path)
+	}
 
 	p2 := part2(fallingBytes, xMax, yMax, fallenBytes)
 	fmt.Println("Part 2:", p2)
@@ -2,6 +2,8 @@ package day18
 
 import (
 	"testing"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
 )
 
 var input = []byte(`5,4
@@ -36,12 +38,34 @@ func TestPart1(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	got := part1(fallingBytes, 6, 6, 12)
+	got, _ := part1(fallingBytes, 6, 6, 12)
 	if got != want {
 		t.Errorf("part1(%s) = %d, want %d", input, got, want)
 	}
 }
 
+func TestPart1Path(t *testing.T) {
+	fallingBytes, err := parseData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, path := part1(fallingBytes, 6, 6, 12)
+	if len(path) != steps+1 {
+		t.Fatalf("path has %d positions, want %d", len(path), steps+1)
+	}
+	if path[0] != (coordinates.Coord{}) || path[len(path)-1] != (coordinates.Coord{X: 6, Y: 6}) {
+		t.Errorf("path runs from %v to %v, want from {0 0 0} to {6 6 0}", path[0], path[len(path)-1])
+	}
+	for i, c := range path {
+		if tm, ok := fallingBytes[c]; ok && tm <= 12 {
+			t.Errorf("path passes through corrupted position %v", c)
+		}
+		if i > 0 && coordinates.ManhattanDistance(path[i-1], c) != 1 {
+			t.Errorf("path moves from %v to %v in a single step", path[i-1], c)
+		}
+	}
+}
+
 func TestPart2(t *testing.T) {
 	want := "6,1"
 	fallingBytes, err := parseData(input)
@@ -7,6 +7,8 @@ import (
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 func parseData(data []byte) (map[string]map[string]bool, error) {
@@ -83,6 +85,27 @@ func part2(graph map[string]map[string]bool) string {
 	return strings.Join(maximalCliqueNodes, ",")
 }
 
+// toDOT returns the network of computers as a graph with the computers of the LAN party and the connections between
+// them highlighted.
+func toDOT(graph map[string]map[string]bool, party []string) *dot.Graph {
+	g := dot.New("network", false)
+	for a, connections := range graph {
+		g.Node(a, nil)
+		for b := range connections {
+			if a < b {
+				g.Edge(a, b, nil)
+			}
+		}
+	}
+	g.Highlight(party...)
+	for i, a := range party {
+		for _, b := range party[i+1:] {
+			g.HighlightEdge(a, b)
+		}
+	}
+	return g
+}
+
 func Run(inputFile string) {
 	data, err := os.ReadFile(inputFile)
 	if err != nil {
@@ -98,4 +121,10 @@ func Run(inputFile string) {
 
 	p2 := part2(graph)
 	fmt.Println("Part 2:", p2)
+
+	if dot.Enabled() {
+		if err := dot.Export(toDOT(graph, strings.Split(p2, ","))); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
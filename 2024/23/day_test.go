@@ -1,6 +1,9 @@
 package day23
 
 import (
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -60,3 +63,41 @@ func TestPart2(t *testing.T) {
 		t.Errorf("part2(%s) = %s, want %s", input, got, want)
 	}
 }
+
+func TestToDOT(t *testing.T) {
+	graph, err := parseData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	party := []string{"co", "de", "ka", "ta"}
+	g := toDOT(graph, party)
+	if got := len(g.Nodes()); got != 16 {
+		t.Errorf("toDOT() has %d nodes, want 16", got)
+	}
+	// Each connection is held from both computers but drawn once
+	if got := g.Edges(); got != 32 {
+		t.Errorf("toDOT() has %d edges, want 32", got)
+	}
+	if got := g.HighlightedNodes(); !reflect.DeepEqual(got, party) {
+		t.Errorf("toDOT() highlights nodes %v, want %v", got, party)
+	}
+
+	// Every connection within the party is highlighted, in whichever direction it was drawn
+	var got [][2]string
+	for _, e := range g.HighlightedEdges() {
+		if e[1] < e[0] {
+			e[0], e[1] = e[1], e[0]
+		}
+		got = append(got, e)
+	}
+	slices.SortFunc(got, func(a, b [2]string) int {
+		if a[0] != b[0] {
+			return strings.Compare(a[0], b[0])
+		}
+		return strings.Compare(a[1], b[1])
+	})
+	want := [][2]string{{"co", "de"}, {"co", "ka"}, {"co", "ta"}, {"de", "ka"}, {"de", "ta"}, {"ka", "ta"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toDOT() highlights edges %v, want %v", got, want)
+	}
+}
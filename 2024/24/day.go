@@ -4,11 +4,15 @@ package day24
 import (
 	"fmt"
 	"log"
+	"math/bits"
+	"math/rand"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
-	"sync"
+
+	"github.com/maze-mapper/advent-of-code/circuit"
+	"github.com/maze-mapper/advent-of-code/dot"
 )
 
 type logicGate struct {
@@ -52,97 +56,320 @@ func parseData(data []byte) (map[string]bool, []logicGate, error) {
 }
 
 func part1(inputWires map[string]bool, gates []logicGate) int {
-	allWires := map[string]bool{}
-	for wire, value := range inputWires {
-		allWires[wire] = value
+	c, err := newCircuit(gates)
+	if err != nil {
+		log.Fatal(err)
+	}
+	wires, err := c.Evaluate(inputWires)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return numberFromBits(wires, "z")
+}
+
+// newCircuit returns the circuit formed by the gates.
+func newCircuit(gates []logicGate) (*circuit.Circuit[bool], error) {
+	circuitGates := make([]circuit.Gate[bool], len(gates))
+	for i, gate := range gates {
+		circuitGates[i] = circuit.Gate[bool]{
+			Op:     circuit.Op(gate.operator),
+			Inputs: []circuit.Input[bool]{circuit.Wire[bool](gate.inputWire1), circuit.Wire[bool](gate.inputWire2)},
+			Output: gate.outputWire,
+		}
+	}
+	return circuit.New(circuit.Bool, circuitGates)
+}
+
+// toDOT returns the circuit formed by the gates as a graph with the wires which were swapped highlighted.
+func toDOT(gates []logicGate, swapped []string) (*dot.Graph, error) {
+	c, err := newCircuit(gates)
+	if err != nil {
+		return nil, err
 	}
+	g := c.Graph()
+	g.Highlight(swapped...)
+	return g, nil
+}
+
+func part2(inputWires map[string]bool, gates []logicGate) (string, error) {
+	swapped, err := findSwaps(inputWires, gates, 4, func(x, y int) int { return x + y })
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(swapped, ","), nil
+}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
+// wireName returns the name of the nth wire with a prefix.
+func wireName(prefix string, n int) string {
+	return fmt.Sprintf("%s%02d", prefix, n)
+}
+
+// countWires returns the number of wires with a prefix.
+func countWires(inputWires map[string]bool, gates []logicGate, prefix string) int {
+	n := 0
+	for wire := range inputWires {
+		if strings.HasPrefix(wire, prefix) {
+			n++
+		}
+	}
 	for _, gate := range gates {
-		gate := gate
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			var input1, input2 bool
-			var ok1, ok2 bool
-			for !(ok1 && ok2) {
-				mu.Lock()
-				input1, ok1 = allWires[gate.inputWire1]
-				input2, ok2 = allWires[gate.inputWire2]
-				mu.Unlock()
-			}
-			var output bool
-			switch gate.operator {
-			case "AND":
-				output = input1 && input2
-			case "OR":
-				output = input1 || input2
-			case "XOR":
-				output = input1 != input2
+		if strings.HasPrefix(gate.outputWire, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// probe is a pair of input numbers used to test the circuit.
+type probe struct {
+	x, y int
+}
+
+// makeProbes returns inputs which exercise every bit of the circuit on its own,
+// together with carries into every bit and some random inputs.
+func makeProbes(bits int) []probe {
+	mask := 1<<bits - 1
+	var probes []probe
+	for i := 0; i < bits; i++ {
+		bit := 1 << i
+		probes = append(probes,
+			probe{x: bit, y: 0},
+			probe{x: 0, y: bit},
+			probe{x: bit, y: bit},
+			probe{x: (bit - 1) & mask, y: 1},
+			probe{x: mask &^ (bit - 1), y: bit},
+		)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		probes = append(probes, probe{x: r.Intn(mask + 1), y: r.Intn(mask + 1)})
+	}
+	return probes
+}
+
+// testBench holds the information needed to probe the gates.
+type testBench struct {
+	xBits, zBits int
+	probes       []probe
+	want         func(x, y int) int
+}
+
+// firstFailingBit returns the lowest output bit which is wrong for any probe,
+// or the number of output bits if every probe is correct.
+func (c *testBench) firstFailingBit(gates []logicGate) (int, error) {
+	inputWires := make(map[string]bool, 2*c.xBits)
+	names := make([]string, 0, 2*c.xBits)
+	for i := 0; i < c.xBits; i++ {
+		names = append(names, wireName("x", i), wireName("y", i))
+	}
+	gateCircuit, err := newCircuit(gates)
+	if err != nil {
+		return 0, err
+	}
+	program, err := gateCircuit.Compile(names)
+	if err != nil {
+		return 0, err
+	}
+
+	first := c.zBits
+	for _, p := range c.probes {
+		for i := 0; i < c.xBits; i++ {
+			inputWires[wireName("x", i)] = p.x&(1<<i) != 0
+			inputWires[wireName("y", i)] = p.y&(1<<i) != 0
+		}
+		wires, err := program.Run(inputWires)
+		if err != nil {
+			return 0, err
+		}
+		got := numberFromBits(wires, "z")
+		want := c.want(p.x, p.y) & (1<<c.zBits - 1)
+		if diff := got ^ want; diff != 0 {
+			first = min(first, bits.TrailingZeros(uint(diff)))
+		}
+	}
+	return first, nil
+}
+
+// nearbyWires returns the gate output wires within a few gates of the given outputs.
+func nearbyWires(gates []logicGate, outputs []string) []string {
+	producers := map[string]logicGate{}
+	for _, gate := range gates {
+		producers[gate.outputWire] = gate
+	}
+	seen := map[string]bool{}
+	frontier := outputs
+	for depth := 0; depth < 4; depth++ {
+		var next []string
+		for _, wire := range frontier {
+			gate, ok := producers[wire]
+			if !ok || seen[wire] {
+				continue
 			}
-			mu.Lock()
-			allWires[gate.outputWire] = output
-			mu.Unlock()
-
-		}()
-	}
-	wg.Wait()
-
-	return numberFromBits(allWires, "z")
-}
-
-func part2(inputWires map[string]bool, gates []logicGate) string {
-	// Solved by inspecting graph with GraphViz and then looking for the least
-	// significant bit where the expected answer differs from the gate output.
-
-	// fmt.Println(graphViz(gates))
-	// x := numberFromBits(inputWires, "x")
-	// y := numberFromBits(inputWires, "y")
-	// z := part1(inputWires, gates)
-	// fmt.Printf("want: %b, %d\n", x+y, x+y)
-	// fmt.Printf("got:  %b, %d\n", z, z)
-
-	swaps := map[string]string{
-		// z19 should be output from XOR.
-		"z19": "vvf",
-		"vvf": "z19",
-		// z37 should be output from XOR.
-		"z37": "nvh",
-		"nvh": "z37",
-		// Swap AND and XOR outputs near x23 and y23.
-		"fgn": "dck",
-		"dck": "fgn",
-		// z12 should be output from XOR.
-		"qdg": "z12",
-		"z12": "qdg",
+			seen[wire] = true
+			next = append(next, gate.inputWire1, gate.inputWire2)
+		}
+		frontier = next
+	}
+	var wires []string
+	for wire := range seen {
+		wires = append(wires, wire)
 	}
+	slices.Sort(wires)
+	return wires
+}
+
+// swapOutputs swaps the output wires of two gates.
+func swapOutputs(gates []logicGate, a, b string) {
 	for i := range gates {
-		outputWire := gates[i].outputWire
-		if swap, ok := swaps[outputWire]; ok {
-			gates[i].outputWire = swap
+		switch gates[i].outputWire {
+		case a:
+			gates[i].outputWire = b
+		case b:
+			gates[i].outputWire = a
+		}
+	}
+}
+
+// findSwaps finds at most pairs swaps of gate outputs which make the circuit compute want for every probe.
+// Swaps are searched for around the lowest failing output bit, also considering wires which break the
+// rules of a ripple-carry adder, and are only kept if they move the lowest failing bit higher.
+// The swapped wires are returned in sorted order.
+func findSwaps(inputWires map[string]bool, gates []logicGate, pairs int, want func(x, y int) int) ([]string, error) {
+	xBits := countWires(inputWires, gates, "x")
+	c := &testBench{
+		xBits:  xBits,
+		zBits:  countWires(inputWires, gates, "z"),
+		probes: makeProbes(xBits),
+		want:   want,
+	}
+	gates = slices.Clone(gates)
+
+	var adderWires []string
+	for _, v := range checkAdder(gates) {
+		adderWires = append(adderWires, v.wire)
+	}
+
+	var swapped []string
+	var search func(remaining int) (bool, error)
+	search = func(remaining int) (bool, error) {
+		first, err := c.firstFailingBit(gates)
+		if err != nil {
+			return false, err
 		}
+		if first == c.zBits {
+			return true, nil
+		}
+		if remaining == 0 {
+			return false, nil
+		}
+
+		candidates := nearbyWires(gates, []string{wireName("z", first), wireName("z", first+1)})
+		for _, wire := range adderWires {
+			if !slices.Contains(candidates, wire) {
+				candidates = append(candidates, wire)
+			}
+		}
+		for i, a := range candidates {
+			for _, b := range candidates[i+1:] {
+				if slices.Contains(swapped, a) || slices.Contains(swapped, b) {
+					continue
+				}
+				swapOutputs(gates, a, b)
+				// Swaps which create a cycle are not valid.
+				if next, err := c.firstFailingBit(gates); err == nil && next > first {
+					swapped = append(swapped, a, b)
+					found, err := search(remaining - 1)
+					if err != nil || found {
+						return found, err
+					}
+					swapped = swapped[:len(swapped)-2]
+				}
+				swapOutputs(gates, a, b)
+			}
+		}
+		return false, nil
 	}
 
-	var swappedWires []string
-	for k := range swaps {
-		swappedWires = append(swappedWires, k)
+	found, err := search(pairs)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no %d swaps of output wires fix the circuit", pairs)
 	}
-	slices.Sort(swappedWires)
-	return strings.Join(swappedWires, ",")
+	slices.Sort(swapped)
+	return swapped, nil
 }
 
-func graphViz(gates []logicGate) string {
-	var b strings.Builder
-	b.WriteString("digraph {\n")
-	for i, gate := range gates {
-		b.WriteString(fmt.Sprintf("  %d [label=\"%s\"]\n", i, gate.operator))
-		b.WriteString(fmt.Sprintf("  %s -> %d\n", gate.inputWire1, i))
-		b.WriteString(fmt.Sprintf("  %s -> %d\n", gate.inputWire2, i))
-		b.WriteString(fmt.Sprintf("  %d -> %s\n", i, gate.outputWire))
+// ruleViolation is a wire which breaks a rule of a ripple-carry adder.
+type ruleViolation struct {
+	wire, rule string
+}
+
+func (v ruleViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.wire, v.rule)
+}
+
+// checkAdder checks the gates against the structure of a ripple-carry adder where
+// z_i = x_i XOR y_i XOR c_(i-1) and c_i = (x_i AND y_i) OR ((x_i XOR y_i) AND c_(i-1)).
+func checkAdder(gates []logicGate) []ruleViolation {
+	xBits := 0
+	consumers := map[string][]string{}
+	for _, gate := range gates {
+		consumers[gate.inputWire1] = append(consumers[gate.inputWire1], gate.operator)
+		consumers[gate.inputWire2] = append(consumers[gate.inputWire2], gate.operator)
+	}
+	for wire := range consumers {
+		if strings.HasPrefix(wire, "x") {
+			xBits++
+		}
 	}
-	b.WriteString("}")
-	return b.String()
+	lastZ := wireName("z", xBits)
+	isInput := func(wire string) bool {
+		return strings.HasPrefix(wire, "x") || strings.HasPrefix(wire, "y")
+	}
+	isFirstBit := func(gate logicGate) bool {
+		return gate.inputWire1 == "x00" || gate.inputWire1 == "y00"
+	}
+
+	var violations []ruleViolation
+	add := func(wire, rule string) {
+		violations = append(violations, ruleViolation{wire: wire, rule: rule})
+	}
+	for _, gate := range gates {
+		out := gate.outputWire
+		isZ := strings.HasPrefix(out, "z")
+		switch gate.operator {
+		case "XOR":
+			switch {
+			case !isInput(gate.inputWire1) && !isZ:
+				add(out, "XOR of a half sum and carry must output a z wire")
+			case isInput(gate.inputWire1) && !isFirstBit(gate) && isZ:
+				add(out, "XOR of x and y inputs must not output a z wire")
+			case isInput(gate.inputWire1) && !isFirstBit(gate) && !(slices.Contains(consumers[out], "XOR") && slices.Contains(consumers[out], "AND")):
+				add(out, "half sum must feed an XOR and an AND gate")
+			case isFirstBit(gate) && out != "z00":
+				add(out, "x00 XOR y00 must output z00")
+			}
+		case "AND":
+			switch {
+			case isZ && !(xBits == 1 && out == lastZ):
+				add(out, "AND gate must not output a z wire")
+			case !isFirstBit(gate) && slices.ContainsFunc(consumers[out], func(op string) bool { return op != "OR" }):
+				add(out, "AND gate must only feed OR gates")
+			}
+		case "OR":
+			if isZ && out != lastZ {
+				add(out, "OR gate must only output the final carry "+lastZ)
+			} else if !isZ && !(slices.Contains(consumers[out], "XOR") && slices.Contains(consumers[out], "AND")) {
+				add(out, "carry must feed an XOR and an AND gate")
+			}
+		}
+	}
+	slices.SortFunc(violations, func(a, b ruleViolation) int {
+		return strings.Compare(a.wire, b.wire)
+	})
+	return violations
 }
 
 func numberFromBits(wires map[string]bool, prefix string) int {
@@ -175,6 +402,22 @@ func Run(inputFile string) {
 	p1 := part1(inputWires, gates)
 	fmt.Println("Part 1:", p1)
 
-	p2 := part2(inputWires, gates)
+	for _, v := range checkAdder(gates) {
+		fmt.Println(v)
+	}
+	p2, err := part2(inputWires, gates)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Part 2:", p2)
+
+	if dot.Enabled() {
+		g, err := toDOT(gates, strings.Split(p2, ","))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := dot.Export(g); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
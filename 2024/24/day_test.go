@@ -1,6 +1,9 @@
 package day24
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -115,14 +118,147 @@ func TestPart1(t *testing.T) {
 
 }
 
-// func TestPart2(t *testing.T) {
-// 	want := "z00,z01,z02,z05"
-// 	inputWires, gates, err := parseData(input3)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// 	got := part2(inputWires, gates)
-// 	if got != want {
-// 		t.Errorf("part2(%s) = %s, want %s", input3, got, want)
-// 	}
-// }
+func TestPart2(t *testing.T) {
+	want := []string{"z00", "z01", "z02", "z05"}
+	inputWires, gates, err := parseData(input3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := findSwaps(inputWires, gates, 2, func(x, y int) int { return x & y })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findSwaps(%s) = %v, want %v", input3, got, want)
+	}
+}
+
+func TestToDOT(t *testing.T) {
+	_, gates, err := parseData(input3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapped := []string{"z00", "z01", "z02", "z05"}
+	g, err := toDOT(gates, swapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each of the 6 gates is drawn between its 2 input wires and its output wire
+	if got := len(g.Nodes()); got != 24 {
+		t.Errorf("toDOT() has %d nodes, want 24", got)
+	}
+	if got := g.Edges(); got != 18 {
+		t.Errorf("toDOT() has %d edges, want 18", got)
+	}
+	if got := g.HighlightedNodes(); !slices.Equal(got, swapped) {
+		t.Errorf("toDOT() highlights %v, want %v", got, swapped)
+	}
+}
+
+// rippleCarryAdder returns the input section and gates of a ripple-carry adder for numbers with the given number of bits.
+func rippleCarryAdder(bits int) string {
+	var inputs, gates []string
+	carry := "c00"
+	for i := 0; i < bits; i++ {
+		inputs = append(inputs, fmt.Sprintf("x%02d: 0", i), fmt.Sprintf("y%02d: 0", i))
+		if i == 0 {
+			gates = append(gates, "x00 XOR y00 -> z00", "x00 AND y00 -> c00")
+			continue
+		}
+		next := fmt.Sprintf("c%02d", i)
+		if i == bits-1 {
+			next = fmt.Sprintf("z%02d", bits)
+		}
+		gates = append(gates,
+			fmt.Sprintf("x%02d XOR y%02d -> s%02d", i, i, i),
+			fmt.Sprintf("s%02d XOR %s -> z%02d", i, carry, i),
+			fmt.Sprintf("x%02d AND y%02d -> a%02d", i, i, i),
+			fmt.Sprintf("s%02d AND %s -> b%02d", i, carry, i),
+			fmt.Sprintf("a%02d OR b%02d -> %s", i, i, next),
+		)
+		carry = next
+	}
+	return strings.Join(inputs, "\n") + "\n\n" + strings.Join(gates, "\n")
+}
+
+func TestPart2Adder(t *testing.T) {
+	tests := []struct {
+		name  string
+		swaps [][2]string
+	}{
+		{name: "No_Swaps"},
+		{name: "Sum_And_Carry", swaps: [][2]string{{"z05", "c05"}}},
+		{name: "Half_Sum_And_Carry_Term", swaps: [][2]string{{"s09", "a09"}}},
+		{name: "Four_Swaps", swaps: [][2]string{{"z03", "b03"}, {"s07", "a07"}, {"z10", "c10"}, {"b13", "z13"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inputWires, gates, err := parseData([]byte(rippleCarryAdder(16)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for _, swap := range tc.swaps {
+				swapOutputs(gates, swap[0], swap[1])
+				want = append(want, swap[0], swap[1])
+			}
+			slices.Sort(want)
+			if len(tc.swaps) == 0 && len(checkAdder(gates)) != 0 {
+				t.Errorf("checkAdder() = %v, want no violations", checkAdder(gates))
+			}
+
+			got, err := part2(inputWires, gates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != strings.Join(want, ",") {
+				t.Errorf("part2() = %s, want %s", got, strings.Join(want, ","))
+			}
+		})
+	}
+}
+
+func TestCheckAdderRules(t *testing.T) {
+	tests := []struct {
+		name string
+		swap [2]string
+		want []ruleViolation
+	}{
+		{
+			name: "Sum_And_Carry",
+			swap: [2]string{"z02", "c02"},
+			want: []ruleViolation{
+				{wire: "c02", rule: "XOR of a half sum and carry must output a z wire"},
+				{wire: "z02", rule: "OR gate must only output the final carry z04"},
+			},
+		},
+		{
+			name: "Half_Sum_And_Carry_Term",
+			swap: [2]string{"s01", "a01"},
+			want: []ruleViolation{
+				{wire: "a01", rule: "half sum must feed an XOR and an AND gate"},
+				{wire: "s01", rule: "AND gate must only feed OR gates"},
+			},
+		},
+		{
+			name: "Sum_And_Carry_Term",
+			swap: [2]string{"z01", "a01"},
+			want: []ruleViolation{
+				{wire: "a01", rule: "XOR of a half sum and carry must output a z wire"},
+				{wire: "z01", rule: "AND gate must not output a z wire"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, gates, err := parseData([]byte(rippleCarryAdder(4)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			swapOutputs(gates, tc.swap[0], tc.swap[1])
+			if got := checkAdder(gates); !slices.Equal(got, tc.want) {
+				t.Errorf("checkAdder() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Frame recording for grid simulations
+package animate
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+// Default recording settings
+const (
+	defaultMaxFrames = 200
+	defaultDelay     = 5
+)
+
+// Image size limits, shared with the other packages which draw grids as images
+const (
+	// maxImageSize is the largest width or height of an image after scaling each cell up to a square of pixels
+	maxImageSize = 800
+	// MaxCellSize is the largest size in pixels of a cell, used for small grids
+	MaxCellSize = 8
+)
+
+// CellSize returns the size in pixels of each cell so that a grid of width by height cells fits in the largest image
+// size
+func CellSize(width, height int) int {
+	return max(1, min(MaxCellSize, maxImageSize/max(width, height, 1)))
+}
+
+// Palette maps the runes of a grid to the colours they are drawn with
+type Palette map[rune]color.Color
+
+// DefaultPalette holds colours for runes commonly used by the puzzles.
+// Runes which are not in the palette are drawn in grey.
+var DefaultPalette = Palette{
+	0:   color.Black,
+	' ': color.Black,
+	'.': color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff},
+	'#': color.RGBA{R: 0xa0, G: 0xa0, B: 0xa0, A: 0xff},
+	'@': color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff},
+	'o': color.RGBA{R: 0xf0, G: 0xc0, B: 0x60, A: 0xff},
+	'O': color.RGBA{R: 0xf0, G: 0xc0, B: 0x60, A: 0xff},
+	'+': color.RGBA{R: 0x40, G: 0xe0, B: 0xff, A: 0xff},
+	'|': color.RGBA{R: 0x40, G: 0xe0, B: 0xff, A: 0xff},
+	'~': color.RGBA{R: 0x20, G: 0x60, B: 0xff, A: 0xff},
+	'E': color.RGBA{R: 0x40, G: 0xff, B: 0x40, A: 0xff},
+	'G': color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff},
+	'^': color.RGBA{R: 0xff, G: 0xff, B: 0x40, A: 0xff},
+	'>': color.RGBA{R: 0xff, G: 0xff, B: 0x40, A: 0xff},
+	'v': color.RGBA{R: 0xff, G: 0xff, B: 0x40, A: 0xff},
+	'<': color.RGBA{R: 0xff, G: 0xff, B: 0x40, A: 0xff},
+	'X': color.RGBA{R: 0xff, G: 0x00, B: 0xff, A: 0xff},
+}
+
+// unknown is the colour of runes which are not in the palette
+var unknown = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+
+// Colour returns the colour of a rune, or grey if it is not in the palette
+func (p Palette) Colour(r rune) color.Color {
+	if c, ok := p[r]; ok {
+		return c
+	}
+	return unknown
+}
+
+// Recorder collects frames of a grid simulation, one cell per rune.
+// Only every few frames are kept once the limit is reached so that long simulations use bounded memory.
+type Recorder struct {
+	// Palette holds the colours of runes in frames recorded after it is set
+	Palette Palette
+	// MaxFrames is the largest number of frames kept
+	MaxFrames int
+	// Delay is the time between frames of an animated GIF in hundredths of a second
+	Delay int
+
+	colours color.Palette
+	index   map[rune]uint8
+	frames  []*image.Paletted
+	ticks   int
+	stride  int
+}
+
+// NewRecorder creates a recorder using the default palette
+func NewRecorder() *Recorder {
+	return &Recorder{
+		Palette:   DefaultPalette,
+		MaxFrames: defaultMaxFrames,
+		Delay:     defaultDelay,
+		colours:   color.Palette{color.Black},
+		index:     map[rune]uint8{},
+		stride:    1,
+	}
+}
+
+// colour returns the index in the image palette of a rune, adding its colour if it is new
+func (r *Recorder) colour(c rune) uint8 {
+	if i, ok := r.index[c]; ok {
+		return i
+	}
+	col := r.Palette.Colour(c)
+	// Runes beyond the size of a GIF palette share the background colour
+	i := uint8(0)
+	if len(r.colours) < 256 {
+		i = uint8(len(r.colours))
+		r.colours = append(r.colours, col)
+	}
+	r.index[c] = i
+	return i
+}
+
+// Frame records a frame of the grid
+func (r *Recorder) Frame(grid [][]rune) {
+	r.ticks++
+	if (r.ticks-1)%r.stride != 0 {
+		return
+	}
+
+	width := 0
+	for _, row := range grid {
+		width = max(width, len(row))
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, len(grid)), nil)
+	for y, row := range grid {
+		for x, c := range row {
+			img.Pix[y*img.Stride+x] = r.colour(c)
+		}
+	}
+	r.frames = append(r.frames, img)
+
+	// Halve the number of frames kept when there are too many
+	if len(r.frames) > r.MaxFrames && r.MaxFrames > 1 {
+		kept := r.frames[:0]
+		for i, f := range r.frames {
+			if i%2 == 0 {
+				kept = append(kept, f)
+			}
+		}
+		r.frames = kept
+		r.stride *= 2
+	}
+}
+
+// Len returns the number of frames kept
+func (r *Recorder) Len() int {
+	return len(r.frames)
+}
+
+// images returns the frames scaled up so that each cell is a square of pixels, all drawn on the same size canvas
+func (r *Recorder) images() []*image.Paletted {
+	width, height := 1, 1
+	for _, f := range r.frames {
+		width = max(width, f.Rect.Dx())
+		height = max(height, f.Rect.Dy())
+	}
+	scale := CellSize(width, height)
+
+	images := make([]*image.Paletted, len(r.frames))
+	for i, f := range r.frames {
+		img := image.NewPaletted(image.Rect(0, 0, width*scale, height*scale), r.colours)
+		for y := 0; y < f.Rect.Dy(); y++ {
+			for x := 0; x < f.Rect.Dx(); x++ {
+				c := f.Pix[y*f.Stride+x]
+				for dy := 0; dy < scale; dy++ {
+					start := (y*scale+dy)*img.Stride + x*scale
+					for dx := 0; dx < scale; dx++ {
+						img.Pix[start+dx] = c
+					}
+				}
+			}
+		}
+		images[i] = img
+	}
+	return images
+}
+
+// WriteGIF writes the frames as an animated GIF which loops forever
+func (r *Recorder) WriteGIF(w io.Writer) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+	anim := &gif.GIF{Image: r.images()}
+	anim.Delay = make([]int, len(anim.Image))
+	for i := range anim.Delay {
+		anim.Delay[i] = r.Delay
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// WritePNGs writes each frame as a numbered PNG file in a directory, which is created if needed
+func (r *Recorder) WritePNGs(dir string) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, img := range r.images() {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("frame%05d.png", i)))
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(f, img); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes the frames as an animated GIF if the path ends in .gif, otherwise as PNG files in a directory
+func (r *Recorder) Save(path string) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".gif") {
+		return r.WritePNGs(path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteGIF(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// active is the recorder used by the package level functions, nil unless recording has been started
+var active *Recorder
+
+// activePath is where the active recorder is saved
+var activePath string
+
+// Start begins recording the frames passed to Frame, to be saved to path by Finish
+func Start(path string) {
+	active = NewRecorder()
+	activePath = path
+}
+
+// Enabled returns whether frames are being recorded, so simulations can avoid building frames which are not needed
+func Enabled() bool {
+	return active != nil
+}
+
+// UsePalette sets the palette used for runes not yet seen in recorded frames
+func UsePalette(p Palette) {
+	if active != nil {
+		active.Palette = p
+	}
+}
+
+// Frame records a frame of the grid if recording has been started.
+// Simulations call this once per tick.
+func Frame(grid [][]rune) {
+	if active != nil {
+		active.Frame(grid)
+	}
+}
+
+// Finish saves the recorded frames if recording has been started.
+// Puzzles which are not simulated on a grid record no frames, which is warned about rather than treated as an error.
+func Finish() error {
+	if active == nil {
+		return nil
+	}
+	if active.Len() == 0 {
+		log.Printf("No frames recorded, %s was not written", activePath)
+		return nil
+	}
+	return active.Save(activePath)
+}
+
+// Points returns a grid of the bounding box of a set of points, with points drawn as the given rune and other cells
+// as '.'
+func Points[V any](points map[coordinates.Coord]V, r rune) [][]rune {
+	if len(points) == 0 {
+		return nil
+	}
+	coords := make([]coordinates.Coord, 0, len(points))
+	for c := range points {
+		coords = append(coords, c)
+	}
+	minCoord, maxCoord := coordinates.Range(coords)
+
+	grid := make([][]rune, maxCoord.Y-minCoord.Y+1)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(".", maxCoord.X-minCoord.X+1))
+	}
+	for _, c := range coords {
+		grid[c.Y-minCoord.Y][c.X-minCoord.X] = r
+	}
+	return grid
+}
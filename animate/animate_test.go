@@ -0,0 +1,108 @@
+package animate
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+func TestWriteGIF(t *testing.T) {
+	r := NewRecorder()
+	r.Palette = Palette{'#': color.White, '.': color.Black}
+	r.Frame([][]rune{[]rune("#."), []rune(".#")})
+	// Frames of different sizes are drawn on the same canvas
+	r.Frame([][]rune{[]rune("#.#")})
+
+	var buf bytes.Buffer
+	if err := r.WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("GIF has %d frames, want 2", len(anim.Image))
+	}
+	img := anim.Image[0]
+	scale := img.Rect.Dx() / 3
+	if img.Rect.Dx() != 3*scale || img.Rect.Dy() != 2*scale || scale != MaxCellSize {
+		t.Errorf("frame size is %v, want %d by %d", img.Rect, 3*MaxCellSize, 2*MaxCellSize)
+	}
+	if got := color.GrayModel.Convert(img.At(0, 0)).(color.Gray).Y; got != 0xff {
+		t.Errorf("top left pixel has brightness %d, want 255", got)
+	}
+	if got := color.GrayModel.Convert(img.At(scale, 0)).(color.Gray).Y; got != 0 {
+		t.Errorf("second pixel has brightness %d, want 0", got)
+	}
+}
+
+func TestMaxFrames(t *testing.T) {
+	r := NewRecorder()
+	r.MaxFrames = 4
+	for i := 0; i < 20; i++ {
+		r.Frame([][]rune{{'#'}})
+	}
+	if r.Len() > r.MaxFrames {
+		t.Errorf("recorder kept %d frames, want at most %d", r.Len(), r.MaxFrames)
+	}
+	if r.Len() < r.MaxFrames/2 {
+		t.Errorf("recorder kept %d frames, want at least %d", r.Len(), r.MaxFrames/2)
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+
+	Start(filepath.Join(dir, "frames"))
+	if !Enabled() {
+		t.Fatal("recording is not enabled after Start")
+	}
+	for i := 0; i < 3; i++ {
+		Frame([][]rune{[]rune("#.#")})
+	}
+	if err := Finish(); err != nil {
+		t.Fatal(err)
+	}
+	active = nil
+
+	files, err := os.ReadDir(filepath.Join(dir, "frames"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Errorf("wrote %d PNG files, want 3", len(files))
+	}
+
+	if err := NewRecorder().Save(filepath.Join(dir, "empty.gif")); err == nil {
+		t.Errorf("Save() with no frames returned no error")
+	}
+
+	Start(filepath.Join(dir, "none.gif"))
+	defer func() { active = nil }()
+	if err := Finish(); err != nil {
+		t.Errorf("Finish() with no frames = %v, want nil", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "none.gif")); !os.IsNotExist(err) {
+		t.Errorf("Finish() with no frames wrote a file")
+	}
+}
+
+func TestPoints(t *testing.T) {
+	points := map[coordinates.Coord]struct{}{{X: -1, Y: 2}: {}, {X: 1, Y: 3}: {}}
+	grid := Points(points, '#')
+	want := []string{"#..", "..#"}
+	if len(grid) != len(want) {
+		t.Fatalf("Points() has %d rows, want %d", len(grid), len(want))
+	}
+	for i, row := range grid {
+		if string(row) != want[i] {
+			t.Errorf("Points() row %d = %q, want %q", i, string(row), want[i])
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Logic circuit simulator
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/maze-mapper/advent-of-code/dot"
+)
+
+// ErrCycle is returned when a wire depends on its own value
+var ErrCycle = errors.New("circuit has a cycle")
+
+// Op is the operation carried out by a gate
+type Op string
+
+// Operations of gates
+const (
+	// Buffer passes its single input straight through
+	Buffer Op = ""
+	And    Op = "AND"
+	Or     Op = "OR"
+	Xor    Op = "XOR"
+	Not    Op = "NOT"
+	LShift Op = "LSHIFT"
+	RShift Op = "RSHIFT"
+)
+
+// arity is the number of inputs taken by each operation
+var arity = map[Op]int{
+	Buffer: 1,
+	And:    2,
+	Or:     2,
+	Xor:    2,
+	Not:    1,
+	LShift: 1,
+	RShift: 1,
+}
+
+// Width describes the signals carried by wires and how gates combine them
+type Width[T comparable] struct {
+	And, Or, Xor   func(a, b T) T
+	Not            func(a T) T
+	LShift, RShift func(a T, n int) T
+}
+
+// Bool is the width of circuits whose wires carry a single bit
+var Bool = Width[bool]{
+	And: func(a, b bool) bool { return a && b },
+	Or:  func(a, b bool) bool { return a || b },
+	Xor: func(a, b bool) bool { return a != b },
+	Not: func(a bool) bool { return !a },
+	// Shifting a single bit by any amount leaves nothing behind
+	LShift: func(a bool, n int) bool { return a && n == 0 },
+	RShift: func(a bool, n int) bool { return a && n == 0 },
+}
+
+// Uint16 is the width of circuits whose wires carry 16 bit signals
+var Uint16 = Width[uint16]{
+	And:    func(a, b uint16) uint16 { return a & b },
+	Or:     func(a, b uint16) uint16 { return a | b },
+	Xor:    func(a, b uint16) uint16 { return a ^ b },
+	Not:    func(a uint16) uint16 { return ^a },
+	LShift: func(a uint16, n int) uint16 { return a << n },
+	RShift: func(a uint16, n int) uint16 { return a >> n },
+}
+
+// Input is an input to a gate, either a named wire or a constant signal if Wire is empty
+type Input[T comparable] struct {
+	Wire  string
+	Value T
+}
+
+// Wire returns an input connected to a named wire
+func Wire[T comparable](name string) Input[T] {
+	return Input[T]{Wire: name}
+}
+
+// Const returns an input held at a constant signal
+func Const[T comparable](value T) Input[T] {
+	return Input[T]{Value: value}
+}
+
+// String returns the wire name or the constant signal
+func (in Input[T]) String() string {
+	if in.Wire != "" {
+		return in.Wire
+	}
+	return fmt.Sprint(in.Value)
+}
+
+// Gate sets its output wire from the operation applied to its inputs.
+// Shift is the number of bits moved by the shift operations.
+type Gate[T comparable] struct {
+	Op     Op
+	Inputs []Input[T]
+	Shift  int
+	Output string
+}
+
+// String returns the gate written as "x AND y -> z"
+func (g Gate[T]) String() string {
+	var lhs string
+	switch {
+	case g.Op == Buffer:
+		lhs = g.Inputs[0].String()
+	case g.Op == Not:
+		lhs = fmt.Sprintf("NOT %s", g.Inputs[0])
+	case g.Op == LShift || g.Op == RShift:
+		lhs = fmt.Sprintf("%s %s %d", g.Inputs[0], g.Op, g.Shift)
+	default:
+		lhs = fmt.Sprintf("%s %s %s", g.Inputs[0], g.Op, g.Inputs[1])
+	}
+	return lhs + " -> " + g.Output
+}
+
+// Circuit is a set of gates connected by named wires
+type Circuit[T comparable] struct {
+	width     Width[T]
+	gates     []Gate[T]
+	overrides map[string]T
+}
+
+// New creates a circuit of gates carrying signals of the given width.
+// An error is returned if a gate has the wrong number of inputs or two gates set the same wire.
+func New[T comparable](width Width[T], gates []Gate[T]) (*Circuit[T], error) {
+	outputs := map[string]bool{}
+	for _, g := range gates {
+		n, ok := arity[g.Op]
+		if !ok {
+			return nil, fmt.Errorf("gate %s: unknown operation %q", g.Output, g.Op)
+		}
+		if len(g.Inputs) != n {
+			return nil, fmt.Errorf("gate %s: %q takes %d inputs, given %d", g.Output, g.Op, n, len(g.Inputs))
+		}
+		if outputs[g.Output] {
+			return nil, fmt.Errorf("wire %s is set by more than one gate", g.Output)
+		}
+		outputs[g.Output] = true
+	}
+	return &Circuit[T]{width: width, gates: gates, overrides: map[string]T{}}, nil
+}
+
+// Gates returns the gates of the circuit
+func (c *Circuit[T]) Gates() []Gate[T] {
+	return c.gates
+}
+
+// Override holds a wire at a signal, ignoring the gate which would otherwise set it
+func (c *Circuit[T]) Override(wire string, value T) {
+	c.overrides[wire] = value
+}
+
+// Program is a circuit with its gates in an order which may be evaluated for a set of input wires
+type Program[T comparable] struct {
+	width     Width[T]
+	ordered   []Gate[T]
+	inputs    []string
+	overrides map[string]T
+}
+
+// Compile orders the gates so that each comes after the gates which set its inputs, given the names of the wires which
+// are set from outside the circuit.
+// An error wrapping ErrCycle is returned if a wire depends on its own value, or an error if a wire is never set.
+func (c *Circuit[T]) Compile(inputs []string) (*Program[T], error) {
+	external := map[string]bool{}
+	for _, wire := range inputs {
+		external[wire] = true
+	}
+	for wire := range c.overrides {
+		external[wire] = true
+	}
+	producers := map[string]int{}
+	for i, g := range c.gates {
+		if !external[g.Output] {
+			producers[g.Output] = i
+		}
+	}
+
+	p := &Program[T]{width: c.width, inputs: inputs, overrides: maps.Clone(c.overrides)}
+	// state is 0 for unvisited, 1 for in progress and 2 for done
+	state := make([]int, len(c.gates))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			return fmt.Errorf("wire %s depends on itself: %w", c.gates[i].Output, ErrCycle)
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		for _, in := range c.gates[i].Inputs {
+			if in.Wire == "" || external[in.Wire] {
+				continue
+			}
+			j, ok := producers[in.Wire]
+			if !ok {
+				return fmt.Errorf("wire %s is never set", in.Wire)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = 2
+		p.ordered = append(p.ordered, c.gates[i])
+		return nil
+	}
+	for i, g := range c.gates {
+		if external[g.Output] {
+			continue
+		}
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Run returns the signal on every wire given the signals on the input wires
+func (p *Program[T]) Run(inputs map[string]T) (map[string]T, error) {
+	wires := make(map[string]T, len(inputs)+len(p.ordered))
+	for _, wire := range p.inputs {
+		value, ok := inputs[wire]
+		if !ok {
+			return nil, fmt.Errorf("no signal given for input wire %s", wire)
+		}
+		wires[wire] = value
+	}
+	for wire, value := range p.overrides {
+		wires[wire] = value
+	}
+
+	var args [2]T
+	for _, g := range p.ordered {
+		for i, in := range g.Inputs {
+			if in.Wire == "" {
+				args[i] = in.Value
+			} else {
+				args[i] = wires[in.Wire]
+			}
+		}
+		var out T
+		switch g.Op {
+		case Buffer:
+			out = args[0]
+		case And:
+			out = p.width.And(args[0], args[1])
+		case Or:
+			out = p.width.Or(args[0], args[1])
+		case Xor:
+			out = p.width.Xor(args[0], args[1])
+		case Not:
+			out = p.width.Not(args[0])
+		case LShift:
+			out = p.width.LShift(args[0], g.Shift)
+		case RShift:
+			out = p.width.RShift(args[0], g.Shift)
+		}
+		wires[g.Output] = out
+	}
+	return wires, nil
+}
+
+// Evaluate returns the signal on every wire given the signals on the input wires
+func (c *Circuit[T]) Evaluate(inputs map[string]T) (map[string]T, error) {
+	names := make([]string, 0, len(inputs))
+	for wire := range inputs {
+		names = append(names, wire)
+	}
+	p, err := c.Compile(names)
+	if err != nil {
+		return nil, err
+	}
+	return p.Run(inputs)
+}
+
+// Graph returns the circuit as a graph with wires as ellipses and gates as boxes.
+// Overridden wires are drawn with their signal and without the gate which would set them.
+func (c *Circuit[T]) Graph() *dot.Graph {
+	g := dot.New("circuit", true)
+	g.Attrs["rankdir"] = "LR"
+
+	for i, gate := range c.gates {
+		if _, ok := c.overrides[gate.Output]; ok {
+			continue
+		}
+		label := string(gate.Op)
+		switch gate.Op {
+		case Buffer:
+			label = "="
+		case LShift, RShift:
+			label = fmt.Sprintf("%s %d", gate.Op, gate.Shift)
+		}
+		id := fmt.Sprintf("g%d", i)
+		g.Node(id, dot.Attrs{"label": label, "shape": "box"})
+		for j, in := range gate.Inputs {
+			if in.Wire == "" {
+				constant := fmt.Sprintf("g%dc%d", i, j)
+				g.Node(constant, dot.Attrs{"label": in.String(), "shape": "plaintext"})
+				g.Edge(constant, id, nil)
+				continue
+			}
+			g.Edge(in.Wire, id, nil)
+		}
+		g.Edge(id, gate.Output, nil)
+	}
+
+	for wire, value := range c.overrides {
+		g.Node(wire, dot.Attrs{"label": fmt.Sprintf("%s = %v", wire, value), "style": "filled"})
+	}
+	return g
+}
+
+// DOT writes the circuit in the GraphViz DOT language, as drawn by Graph
+func (c *Circuit[T]) DOT(w io.Writer) error {
+	return c.Graph().Write(w)
+}
@@ -0,0 +1,135 @@
+package circuit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// exampleGates are the gates of the example circuit from 2015 day 7
+var exampleGates = []Gate[uint16]{
+	{Op: Buffer, Inputs: []Input[uint16]{Const[uint16](123)}, Output: "x"},
+	{Op: Buffer, Inputs: []Input[uint16]{Const[uint16](456)}, Output: "y"},
+	{Op: And, Inputs: []Input[uint16]{Wire[uint16]("x"), Wire[uint16]("y")}, Output: "d"},
+	{Op: Or, Inputs: []Input[uint16]{Wire[uint16]("x"), Wire[uint16]("y")}, Output: "e"},
+	{Op: LShift, Inputs: []Input[uint16]{Wire[uint16]("x")}, Shift: 2, Output: "f"},
+	{Op: RShift, Inputs: []Input[uint16]{Wire[uint16]("y")}, Shift: 2, Output: "g"},
+	{Op: Not, Inputs: []Input[uint16]{Wire[uint16]("x")}, Output: "h"},
+	{Op: Not, Inputs: []Input[uint16]{Wire[uint16]("y")}, Output: "i"},
+}
+
+func TestEvaluate(t *testing.T) {
+	// Reverse the gates so they must be sorted before being evaluated
+	gates := make([]Gate[uint16], len(exampleGates))
+	for i, g := range exampleGates {
+		gates[len(gates)-1-i] = g
+	}
+	c, err := New(Uint16, gates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wires, err := c.Evaluate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint16{"d": 72, "e": 507, "f": 492, "g": 114, "h": 65412, "i": 65079, "x": 123, "y": 456}
+	for wire, value := range want {
+		if wires[wire] != value {
+			t.Errorf("wire %s = %d, want %d", wire, wires[wire], value)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	c, err := New(Uint16, exampleGates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Override("x", 1)
+	wires, err := c.Evaluate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wires["x"] != 1 || wires["f"] != 4 {
+		t.Errorf("wires x and f = %d and %d, want 1 and 4", wires["x"], wires["f"])
+	}
+
+	var sb strings.Builder
+	if err := c.DOT(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), `"x" [label="x = 1", style="filled"];`) {
+		t.Errorf("DOT() does not show the overridden wire:\n%s", sb.String())
+	}
+	if strings.Contains(sb.String(), `"123"`) {
+		t.Errorf("DOT() includes the gate of the overridden wire:\n%s", sb.String())
+	}
+}
+
+func TestBool(t *testing.T) {
+	gates := []Gate[bool]{
+		{Op: Xor, Inputs: []Input[bool]{Wire[bool]("a"), Wire[bool]("b")}, Output: "sum"},
+		{Op: And, Inputs: []Input[bool]{Wire[bool]("a"), Wire[bool]("b")}, Output: "carry"},
+		{Op: Not, Inputs: []Input[bool]{Wire[bool]("carry")}, Output: "nand"},
+		{Op: Or, Inputs: []Input[bool]{Wire[bool]("sum"), Const(false)}, Output: "either"},
+	}
+	c, err := New(Bool, gates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Compile([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []bool{false, true} {
+		for _, b := range []bool{false, true} {
+			wires, err := p.Run(map[string]bool{"a": a, "b": b})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wires["sum"] != (a != b) || wires["carry"] != (a && b) || wires["nand"] != !(a && b) || wires["either"] != (a != b) {
+				t.Errorf("Run(%t, %t) = %v", a, b, wires)
+			}
+		}
+	}
+	if _, err := p.Run(map[string]bool{"a": true}); err == nil {
+		t.Errorf("Run() with a missing input returned no error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cycle := []Gate[bool]{
+		{Op: And, Inputs: []Input[bool]{Wire[bool]("a"), Wire[bool]("c")}, Output: "b"},
+		{Op: Or, Inputs: []Input[bool]{Wire[bool]("a"), Wire[bool]("b")}, Output: "c"},
+	}
+	c, err := New(Bool, cycle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Evaluate(map[string]bool{"a": true}); !errors.Is(err, ErrCycle) {
+		t.Errorf("Evaluate() of a cycle = %v, want %v", err, ErrCycle)
+	}
+	// Overriding a wire in the cycle breaks it
+	c.Override("c", true)
+	if wires, err := c.Evaluate(map[string]bool{"a": true}); err != nil || !wires["b"] {
+		t.Errorf("Evaluate() with override = %v, %v, want b set", wires, err)
+	}
+
+	if _, err := c.Evaluate(nil); err == nil {
+		t.Errorf("Evaluate() with an unset wire returned no error")
+	}
+
+	invalid := [][]Gate[bool]{
+		{{Op: Not, Inputs: []Input[bool]{Wire[bool]("a"), Wire[bool]("b")}, Output: "c"}},
+		{{Op: "NAND", Inputs: []Input[bool]{Wire[bool]("a"), Wire[bool]("b")}, Output: "c"}},
+		{
+			{Op: Buffer, Inputs: []Input[bool]{Wire[bool]("a")}, Output: "c"},
+			{Op: Buffer, Inputs: []Input[bool]{Wire[bool]("b")}, Output: "c"},
+		},
+	}
+	for _, gates := range invalid {
+		if _, err := New(Bool, gates); err == nil {
+			t.Errorf("New(%v) returned no error", gates)
+		}
+	}
+}
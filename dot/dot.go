@@ -0,0 +1,234 @@
+// GraphViz DOT export
+package dot
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Attrs are the attributes of a graph, node or edge, such as label, shape or color
+type Attrs map[string]string
+
+// Highlighted are the attributes added to highlighted nodes and edges
+var Highlighted = Attrs{"color": "red", "penwidth": "3"}
+
+// edge is a connection between two nodes
+type edge struct {
+	from, to string
+	attrs    Attrs
+}
+
+// Graph is a set of nodes connected by edges, which are directed if the graph is.
+// Nodes and edges are written in sorted order so that graphs built by iterating over maps are written the same way
+// every time.
+type Graph struct {
+	Name     string
+	Directed bool
+	// Attrs holds the attributes of the graph itself, such as rankdir
+	Attrs Attrs
+
+	nodes map[string]Attrs
+	edges []*edge
+}
+
+// New creates an empty graph
+func New(name string, directed bool) *Graph {
+	return &Graph{
+		Name:     name,
+		Directed: directed,
+		Attrs:    Attrs{},
+		nodes:    map[string]Attrs{},
+	}
+}
+
+// Node adds a node to the graph if it is not already present and sets its attributes
+func (g *Graph) Node(id string, attrs Attrs) {
+	a, ok := g.nodes[id]
+	if !ok {
+		a = Attrs{}
+		g.nodes[id] = a
+	}
+	for k, v := range attrs {
+		a[k] = v
+	}
+}
+
+// Edge adds an edge between two nodes, adding the nodes if they are not already present.
+// Adding the same edge twice draws it twice.
+func (g *Graph) Edge(from, to string, attrs Attrs) {
+	g.Node(from, nil)
+	g.Node(to, nil)
+	a := Attrs{}
+	for k, v := range attrs {
+		a[k] = v
+	}
+	g.edges = append(g.edges, &edge{from: from, to: to, attrs: a})
+}
+
+// Highlight adds the highlighted attributes to the nodes
+func (g *Graph) Highlight(ids ...string) {
+	for _, id := range ids {
+		g.Node(id, Highlighted)
+	}
+}
+
+// HighlightEdge adds the highlighted attributes to the edges between two nodes.
+// In an undirected graph the edges are matched in either direction.
+func (g *Graph) HighlightEdge(from, to string) {
+	for _, e := range g.edges {
+		if (e.from == from && e.to == to) || (!g.Directed && e.from == to && e.to == from) {
+			for k, v := range Highlighted {
+				e.attrs[k] = v
+			}
+		}
+	}
+}
+
+// Nodes returns the IDs of the nodes in sorted order
+func (g *Graph) Nodes() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Edges returns the number of edges, counting an edge added twice twice
+func (g *Graph) Edges() int {
+	return len(g.edges)
+}
+
+// highlighted returns whether attributes include all of the highlighted attributes
+func highlighted(attrs Attrs) bool {
+	for k, v := range Highlighted {
+		if attrs[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// HighlightedNodes returns the IDs of the highlighted nodes in sorted order
+func (g *Graph) HighlightedNodes() []string {
+	ids := []string{}
+	for _, id := range g.Nodes() {
+		if highlighted(g.nodes[id]) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// HighlightedEdges returns the ends of the highlighted edges in the order they were added
+func (g *Graph) HighlightedEdges() [][2]string {
+	ends := [][2]string{}
+	for _, e := range g.edges {
+		if highlighted(e.attrs) {
+			ends = append(ends, [2]string{e.from, e.to})
+		}
+	}
+	return ends
+}
+
+// quote returns an ID as a DOT string.
+// Backslashes are left alone so that labels may use DOT escapes such as \n.
+func quote(id string) string {
+	return `"` + strings.ReplaceAll(id, `"`, `\"`) + `"`
+}
+
+// attrList returns attributes as a DOT attribute list in sorted order, or an empty string if there are none
+func attrList(attrs Attrs) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + quote(attrs[k])
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+// String returns the graph in the DOT language
+func (g *Graph) String() string {
+	var b strings.Builder
+	kind, op := "graph", "--"
+	if g.Directed {
+		kind, op = "digraph", "->"
+	}
+	fmt.Fprintf(&b, "%s %s {\n", kind, quote(g.Name))
+
+	keys := make([]string, 0, len(g.Attrs))
+	for k := range g.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\t%s=%s;\n", k, quote(g.Attrs[k]))
+	}
+
+	for _, id := range g.Nodes() {
+		fmt.Fprintf(&b, "\t%s%s;\n", quote(id), attrList(g.nodes[id]))
+	}
+
+	edges := make([]*edge, len(g.edges))
+	copy(edges, g.edges)
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+	for _, e := range edges {
+		fmt.Fprintf(&b, "\t%s %s %s%s;\n", quote(e.from), op, quote(e.to), attrList(e.attrs))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Write writes the graph in the DOT language
+func (g *Graph) Write(w io.Writer) error {
+	_, err := io.WriteString(w, g.String())
+	return err
+}
+
+// destination is where the package level Export writes, empty unless exporting has been started
+var destination string
+
+// Start begins exporting the graphs passed to Export to a file, or printing them if the destination is "-"
+func Start(dest string) {
+	destination = dest
+}
+
+// Enabled returns whether graphs are being exported, so puzzles can avoid building graphs which are not needed
+func Enabled() bool {
+	return destination != ""
+}
+
+// Export writes the graph if exporting has been started
+func Export(g *Graph) error {
+	switch destination {
+	case "":
+		return nil
+	case "-":
+		return g.Write(os.Stdout)
+	}
+	f, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	if err := g.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
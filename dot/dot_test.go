@@ -0,0 +1,101 @@
+package dot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	g := New("test", true)
+	g.Attrs["rankdir"] = "LR"
+	g.Edge("b", "c", Attrs{"label": "2"})
+	g.Edge("a", "b", nil)
+	g.Node("a", Attrs{"shape": "box", "label": `say "hi"\n`})
+	g.Highlight("c")
+
+	want := `digraph "test" {
+	rankdir="LR";
+	"a" [label="say \"hi\"\n", shape="box"];
+	"b";
+	"c" [color="red", penwidth="3"];
+	"a" -> "b";
+	"b" -> "c" [label="2"];
+}
+`
+	if got := g.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestHighlightEdge(t *testing.T) {
+	g := New("test", false)
+	g.Edge("a", "b", nil)
+	g.Edge("b", "c", nil)
+	// Undirected edges are matched in either direction
+	g.HighlightEdge("b", "a")
+
+	want := `graph "test" {
+	"a";
+	"b";
+	"c";
+	"a" -- "b" [color="red", penwidth="3"];
+	"b" -- "c";
+}
+`
+	if got := g.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+
+	d := New("test", true)
+	d.Edge("a", "b", nil)
+	d.HighlightEdge("b", "a")
+	if got := d.edges[0].attrs; len(got) != 0 {
+		t.Errorf("directed edge a -> b highlighted by b -> a: %v", got)
+	}
+}
+
+func TestHighlighted(t *testing.T) {
+	g := New("test", false)
+	g.Edge("a", "b", nil)
+	g.Edge("b", "c", nil)
+	g.Edge("c", "a", Attrs{"color": "red"})
+	g.Highlight("b")
+	g.HighlightEdge("a", "c")
+
+	if got, want := g.Nodes(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Nodes() = %v, want %v", got, want)
+	}
+	if got := g.Edges(); got != 3 {
+		t.Errorf("Edges() = %d, want 3", got)
+	}
+	if got, want := g.HighlightedNodes(), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HighlightedNodes() = %v, want %v", got, want)
+	}
+	if got, want := g.HighlightedEdges(), [][2]string{{"c", "a"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HighlightedEdges() = %v, want %v", got, want)
+	}
+}
+
+func TestExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.dot")
+	Start(path)
+	defer Start("")
+
+	if !Enabled() {
+		t.Fatal("Enabled() = false after Start")
+	}
+	g := New("test", false)
+	g.Edge("a", "b", nil)
+	if err := Export(g); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != g.String() {
+		t.Errorf("Export() wrote %q, want %q", data, g.String())
+	}
+}
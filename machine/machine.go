@@ -0,0 +1,333 @@
+// Register machine toolkit
+package machine
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrStepLimit is returned when a program runs for more steps than its limit
+var ErrStepLimit = errors.New("step limit reached")
+
+// ErrLoop is returned when loop detection is enabled and a program returns to an earlier state
+var ErrLoop = errors.New("program is in an infinite loop")
+
+// OperandKind is the kind of value an operand takes
+type OperandKind int
+
+// Kinds of operand
+const (
+	// Register operands are given by register name or index and hold the register index
+	Register OperandKind = iota
+	// Immediate operands are integer literals, which may be signed
+	Immediate
+)
+
+// Op is a named operation in an instruction set
+type Op struct {
+	Name     string
+	Operands []OperandKind
+	// Cycles is the number of cycles the operation takes, with zero taken as one
+	Cycles int
+	// Exec carries out the operation with the instruction's arguments.
+	// The program counter moves to the next instruction afterwards unless Exec calls Jump, Goto or Halt.
+	Exec func(m *Machine, args []int) error
+}
+
+// Set is an instruction set, a register file layout and the operations which act upon it.
+// Programs for a set are parsed with Parse and run on a Machine, which provides execution limits, tracing, loop
+// detection and hooks shared by every instruction set.
+type Set struct {
+	registers []string
+	ops       map[string]*Op
+}
+
+// NewSet creates an instruction set with the named registers and operations
+func NewSet(registers []string, ops ...Op) (*Set, error) {
+	s := &Set{registers: registers, ops: map[string]*Op{}}
+	for i := range ops {
+		op := ops[i]
+		if _, ok := s.ops[op.Name]; ok {
+			return nil, fmt.Errorf("operation %q defined twice", op.Name)
+		}
+		if op.Exec == nil {
+			return nil, fmt.Errorf("operation %q has no Exec function", op.Name)
+		}
+		s.ops[op.Name] = &op
+	}
+	return s, nil
+}
+
+// MustNewSet is like NewSet but panics if the instruction set is invalid, for defining sets as package variables
+func MustNewSet(registers []string, ops ...Op) *Set {
+	s, err := NewSet(registers, ops...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Registers returns the names of the registers
+func (s *Set) Registers() []string {
+	return s.registers
+}
+
+// register returns the index of a register given by name or index
+func (s *Set) register(arg string) (int, error) {
+	for i, name := range s.registers {
+		if name == arg {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(arg); err == nil && i >= 0 && i < len(s.registers) {
+		return i, nil
+	}
+	return 0, fmt.Errorf("unknown register %q", arg)
+}
+
+// Instruction is an operation with its arguments.
+// Register arguments are held as register indices.
+type Instruction struct {
+	Op   *Op
+	Args []int
+}
+
+// Instruction returns the named operation with the given arguments
+func (s *Set) Instruction(name string, args ...int) (Instruction, error) {
+	op, ok := s.ops[name]
+	if !ok {
+		return Instruction{}, fmt.Errorf("unknown operation %q", name)
+	}
+	if len(args) != len(op.Operands) {
+		return Instruction{}, fmt.Errorf("%s takes %d arguments, given %d", name, len(op.Operands), len(args))
+	}
+	for i, kind := range op.Operands {
+		if kind == Register && (args[i] < 0 || args[i] >= len(s.registers)) {
+			return Instruction{}, fmt.Errorf("%s argument %d: register %d does not exist", name, i+1, args[i])
+		}
+	}
+	return Instruction{Op: op, Args: args}, nil
+}
+
+// ParseInstruction parses a single instruction of the operation name followed by its arguments, which are separated
+// by spaces or commas
+func (s *Set) ParseInstruction(line string) (Instruction, error) {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	if len(fields) == 0 {
+		return Instruction{}, fmt.Errorf("empty instruction")
+	}
+	op, ok := s.ops[fields[0]]
+	if !ok {
+		return Instruction{}, fmt.Errorf("unknown operation %q", fields[0])
+	}
+	if len(fields)-1 != len(op.Operands) {
+		return Instruction{}, fmt.Errorf("%s takes %d arguments, given %d", op.Name, len(op.Operands), len(fields)-1)
+	}
+
+	args := make([]int, len(op.Operands))
+	for i, kind := range op.Operands {
+		var err error
+		switch kind {
+		case Register:
+			args[i], err = s.register(fields[i+1])
+		case Immediate:
+			args[i], err = strconv.Atoi(fields[i+1])
+		}
+		if err != nil {
+			return Instruction{}, fmt.Errorf("%s argument %d: %w", op.Name, i+1, err)
+		}
+	}
+	return Instruction{Op: op, Args: args}, nil
+}
+
+// Parse parses a program of one instruction per line
+func (s *Set) Parse(data []byte) ([]Instruction, error) {
+	lines := strings.Split(
+		strings.TrimSuffix(string(data), "\n"), "\n",
+	)
+	program := make([]Instruction, len(lines))
+	for i, line := range lines {
+		inst, err := s.ParseInstruction(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		program[i] = inst
+	}
+	return program, nil
+}
+
+// Format returns an instruction as written in a program, using the register names of the set
+func (s *Set) Format(inst Instruction) string {
+	parts := []string{inst.Op.Name}
+	for i, kind := range inst.Op.Operands {
+		if kind == Register {
+			parts = append(parts, s.registers[inst.Args[i]])
+		} else {
+			parts = append(parts, strconv.Itoa(inst.Args[i]))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Machine runs a program on an instruction set
+type Machine struct {
+	set     *Set
+	program []Instruction
+
+	// Registers holds the register values
+	Registers []int
+	// PC is the index of the next instruction to run
+	PC int
+	// Steps is the number of instructions run
+	Steps int
+	// Cycles is the number of cycles run
+	Cycles int
+	// Output holds the values emitted by the program
+	Output []int
+
+	// MaxSteps stops the program with ErrStepLimit after this many instructions if it is above zero
+	MaxSteps int
+	// DetectLoops stops the program with ErrLoop if the program counter and registers repeat
+	DetectLoops bool
+	// Trace receives a line for every instruction run if it is not nil
+	Trace io.Writer
+	// BeforeStep is called before each instruction is run if it is not nil, an error stops the program
+	BeforeStep func(m *Machine, inst Instruction) error
+	// AfterStep is called after each instruction is run if it is not nil, an error stops the program
+	AfterStep func(m *Machine, inst Instruction) error
+	// OnCycle is called during every cycle if it is not nil, when the registers do not yet reflect the instruction
+	OnCycle func(m *Machine)
+
+	moved  bool
+	halted bool
+	seen   map[string]bool
+}
+
+// New creates a machine to run a program with every register set to zero
+func New(set *Set, program []Instruction) *Machine {
+	return &Machine{
+		set:       set,
+		program:   program,
+		Registers: make([]int, len(set.registers)),
+	}
+}
+
+// Register returns the value of the named register
+func (m *Machine) Register(name string) int {
+	i, err := m.set.register(name)
+	if err != nil {
+		panic(err)
+	}
+	return m.Registers[i]
+}
+
+// SetRegister sets the value of the named register
+func (m *Machine) SetRegister(name string, value int) {
+	i, err := m.set.register(name)
+	if err != nil {
+		panic(err)
+	}
+	m.Registers[i] = value
+}
+
+// Jump moves the program counter relative to the current instruction
+func (m *Machine) Jump(offset int) {
+	m.PC += offset
+	m.moved = true
+}
+
+// Goto moves the program counter to an instruction
+func (m *Machine) Goto(address int) {
+	m.PC = address
+	m.moved = true
+}
+
+// Halt stops the program after the current instruction
+func (m *Machine) Halt() {
+	m.halted = true
+	m.moved = true
+}
+
+// Emit adds a value to the output
+func (m *Machine) Emit(value int) {
+	m.Output = append(m.Output, value)
+}
+
+// Halted returns whether the program has finished, either by halting or the program counter leaving the program
+func (m *Machine) Halted() bool {
+	return m.halted || m.PC < 0 || m.PC >= len(m.program)
+}
+
+// state returns a key for the program counter and register values
+func (m *Machine) state() string {
+	return fmt.Sprint(m.PC, m.Registers)
+}
+
+// Step runs a single instruction and returns false if the program had already finished
+func (m *Machine) Step() (bool, error) {
+	if m.Halted() {
+		return false, nil
+	}
+	if m.MaxSteps > 0 && m.Steps >= m.MaxSteps {
+		return false, fmt.Errorf("instruction %d: %w after %d steps", m.PC, ErrStepLimit, m.Steps)
+	}
+	if m.DetectLoops {
+		if m.seen == nil {
+			m.seen = map[string]bool{}
+		}
+		key := m.state()
+		if m.seen[key] {
+			return false, fmt.Errorf("instruction %d: %w", m.PC, ErrLoop)
+		}
+		m.seen[key] = true
+	}
+
+	inst := m.program[m.PC]
+	if m.BeforeStep != nil {
+		if err := m.BeforeStep(m, inst); err != nil {
+			return false, fmt.Errorf("instruction %d: %w", m.PC, err)
+		}
+	}
+	if m.Trace != nil {
+		fmt.Fprintf(m.Trace, "%d: %s %v\n", m.PC, m.set.Format(inst), m.Registers)
+	}
+
+	cycles := max(inst.Op.Cycles, 1)
+	for c := 0; c < cycles; c++ {
+		m.Cycles++
+		if m.OnCycle != nil {
+			m.OnCycle(m)
+		}
+	}
+
+	m.moved = false
+	pc := m.PC
+	if err := inst.Op.Exec(m, inst.Args); err != nil {
+		return false, fmt.Errorf("instruction %d: %w", pc, err)
+	}
+	if !m.moved {
+		m.PC++
+	}
+	m.Steps++
+	if m.AfterStep != nil {
+		if err := m.AfterStep(m, inst); err != nil {
+			return false, fmt.Errorf("instruction %d: %w", pc, err)
+		}
+	}
+	return true, nil
+}
+
+// Run runs the program until it finishes
+func (m *Machine) Run() error {
+	for {
+		ok, err := m.Step()
+		if err != nil || !ok {
+			return err
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package machine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testSet is a small instruction set with two registers
+var testSet = MustNewSet(
+	[]string{"a", "b"},
+	Op{
+		Name:     "inc",
+		Operands: []OperandKind{Register},
+		Exec: func(m *Machine, args []int) error {
+			m.Registers[args[0]]++
+			return nil
+		},
+	},
+	Op{
+		Name:     "set",
+		Operands: []OperandKind{Register, Immediate},
+		Cycles:   2,
+		Exec: func(m *Machine, args []int) error {
+			m.Registers[args[0]] = args[1]
+			return nil
+		},
+	},
+	Op{
+		Name:     "jnz",
+		Operands: []OperandKind{Register, Immediate},
+		Exec: func(m *Machine, args []int) error {
+			if m.Registers[args[0]] != 0 {
+				m.Jump(args[1])
+			}
+			return nil
+		},
+	},
+	Op{
+		Name:     "out",
+		Operands: []OperandKind{Register},
+		Exec: func(m *Machine, args []int) error {
+			m.Emit(m.Registers[args[0]])
+			return nil
+		},
+	},
+	Op{
+		Name: "hlt",
+		Exec: func(m *Machine, args []int) error {
+			m.Halt()
+			return nil
+		},
+	},
+)
+
+func TestRun(t *testing.T) {
+	program, err := testSet.Parse([]byte("set a, 3\ninc b\nout b\nset a, 0\njnz a, -3\nhlt\ninc b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(testSet, program)
+	if err := m.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Register("b"); got != 1 {
+		t.Errorf("register b = %d, want 1", got)
+	}
+	if m.Steps != 6 || m.Cycles != 8 {
+		t.Errorf("ran %d steps and %d cycles, want 6 and 8", m.Steps, m.Cycles)
+	}
+	if len(m.Output) != 1 || m.Output[0] != 1 {
+		t.Errorf("output = %v, want [1]", m.Output)
+	}
+	if !m.Halted() {
+		t.Errorf("machine has not halted")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"dec a",
+		"inc c",
+		"inc",
+		"set a, b",
+		"jnz a, 1, 2",
+	}
+	for _, tc := range tests {
+		if _, err := testSet.Parse([]byte(tc)); err == nil {
+			t.Errorf("Parse(%q) returned no error", tc)
+		}
+	}
+}
+
+func TestRegisterIndex(t *testing.T) {
+	inst, err := testSet.ParseInstruction("inc 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.Args[0] != 1 {
+		t.Errorf("ParseInstruction(\"inc 1\") register = %d, want 1", inst.Args[0])
+	}
+	if _, err := testSet.Instruction("inc", 2); err == nil {
+		t.Errorf("Instruction(\"inc\", 2) returned no error")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, line := range []string{"set b -3", "inc a", "hlt"} {
+		inst, err := testSet.ParseInstruction(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := testSet.Format(inst); got != line {
+			t.Errorf("Format(ParseInstruction(%q)) = %q", line, got)
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	program, err := testSet.Parse([]byte("set a, 1\njnz a, 0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(testSet, program)
+	m.MaxSteps = 100
+	if err := m.Run(); !errors.Is(err, ErrStepLimit) {
+		t.Errorf("Run() with step limit returned %v, want %v", err, ErrStepLimit)
+	}
+	if m.Steps != 100 {
+		t.Errorf("ran %d steps, want 100", m.Steps)
+	}
+
+	m = New(testSet, program)
+	m.DetectLoops = true
+	if err := m.Run(); !errors.Is(err, ErrLoop) {
+		t.Errorf("Run() with loop detection returned %v, want %v", err, ErrLoop)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	program, err := testSet.Parse([]byte("set a, 2\ninc a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(testSet, program)
+
+	var trace strings.Builder
+	m.Trace = &trace
+	cycles := []int{}
+	m.OnCycle = func(m *Machine) {
+		cycles = append(cycles, m.Register("a"))
+	}
+	after := []int{}
+	m.AfterStep = func(m *Machine, inst Instruction) error {
+		after = append(after, m.PC)
+		return nil
+	}
+	if err := m.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "0: set a 2 [0 0]\n1: inc a [2 0]\n"
+	if trace.String() != want {
+		t.Errorf("trace = %q, want %q", trace.String(), want)
+	}
+	if len(cycles) != 3 || cycles[0] != 0 || cycles[1] != 0 || cycles[2] != 2 {
+		t.Errorf("register a during cycles = %v, want [0 0 2]", cycles)
+	}
+	if len(after) != 2 || after[0] != 1 || after[1] != 2 {
+		t.Errorf("program counter after steps = %v, want [1 2]", after)
+	}
+
+	stop := errors.New("stop")
+	m = New(testSet, program)
+	m.BeforeStep = func(m *Machine, inst Instruction) error {
+		if inst.Op.Name == "inc" {
+			return stop
+		}
+		return nil
+	}
+	if err := m.Run(); !errors.Is(err, stop) {
+		t.Errorf("Run() with failing hook returned %v, want %v", err, stop)
+	}
+}
+
+func TestNewSetErrors(t *testing.T) {
+	exec := func(m *Machine, args []int) error { return nil }
+	if _, err := NewSet(nil, Op{Name: "nop", Exec: exec}, Op{Name: "nop", Exec: exec}); err == nil {
+		t.Errorf("NewSet with a repeated operation returned no error")
+	}
+	if _, err := NewSet(nil, Op{Name: "nop"}); err == nil {
+		t.Errorf("NewSet with no Exec function returned no error")
+	}
+}
@@ -9,11 +9,22 @@ import (
 	aoc2019 "github.com/maze-mapper/advent-of-code/2019"
 	aoc2021 "github.com/maze-mapper/advent-of-code/2021"
 	aoc2022 "github.com/maze-mapper/advent-of-code/2022"
+	"github.com/maze-mapper/advent-of-code/2022/vfs"
 	aoc2023 "github.com/maze-mapper/advent-of-code/2023"
 	aoc2024 "github.com/maze-mapper/advent-of-code/2024"
+	"github.com/maze-mapper/advent-of-code/animate"
+	"github.com/maze-mapper/advent-of-code/dot"
+	"github.com/maze-mapper/advent-of-code/overlay"
 )
 
 func main() {
+	interactive := flag.Bool("interactive", false, "run a 2019 Intcode program interactively on the terminal")
+	record := flag.String("record", "", "file to record interactive input to")
+	replay := flag.String("replay", "", "file of interactive input to replay before reading from the terminal")
+	animation := flag.String("animate", "", "record grid simulations to an animated GIF, or to a directory of PNG files if the name does not end in .gif")
+	route := flag.String("path", "", "draw the paths found by shortest path puzzles, as text if \"-\" or to files named from this as PNG or SVG images if it ends in .png or .svg")
+	graph := flag.String("dot", "", "write the graph of a graph shaped puzzle, or the control flow graph of a 2019 Intcode program, in the GraphViz DOT language to this file, or print it if \"-\"")
+	tree := flag.String("tree", "", "write the filesystem rebuilt from the 2022 day 7 transcript as a tree and du report to this file, or print it if \"-\"")
 	flag.Parse()
 	if flag.NArg() != 3 {
 		log.Fatal("Usage: <year> <day> <inputFile>")
@@ -22,6 +33,30 @@ func main() {
 	day := flag.Arg(1)
 	inputFile := flag.Arg(2)
 
+	if *interactive {
+		if year != "2019" {
+			log.Fatal("Interactive mode is only available for 2019")
+		}
+		aoc2019.Interactive(day, inputFile, *record, *replay)
+		return
+	}
+
+	if *animation != "" {
+		animate.Start(*animation)
+	}
+
+	if *route != "" {
+		overlay.Start(*route)
+	}
+
+	if *graph != "" {
+		dot.Start(*graph)
+	}
+
+	if *tree != "" {
+		vfs.Start(*tree)
+	}
+
 	f := func(s, ss string) {}
 	switch year {
 	case "2015":
@@ -42,4 +77,8 @@ func main() {
 		log.Fatal(year, " is not a valid year")
 	}
 	f(day, inputFile)
+
+	if err := animate.Finish(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,255 @@
+// Positional numerals with any digit alphabet
+package numeral
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// System is a positional numeral system whose digits have consecutive values.
+// In a standard system the lowest digit is zero and negative numbers are written with a leading '-'.
+// In a balanced system there are negative and positive digits, so every integer is written without a sign.
+type System struct {
+	digits []rune
+	lowest int
+	values map[rune]int
+}
+
+// Numeral systems used by the puzzles
+var (
+	Decimal         = MustNew("0123456789", 0)
+	Binary          = MustNew("01", 0)
+	BalancedTernary = MustNew("-0+", -1)
+	// Snafu is the balanced base five system of the hot air balloon fuel requirements
+	Snafu = MustNew("=-012", -2)
+)
+
+// New returns a numeral system of digits listed from lowest to highest value, where the first digit has the value
+// lowest. The base is the number of digits.
+// The digits must include zero and either be standard, starting at zero, or balanced, with a negative and a positive
+// digit, so that every integer can be written.
+func New(digits string, lowest int) (*System, error) {
+	s := &System{digits: []rune(digits), lowest: lowest, values: map[rune]int{}}
+	if len(s.digits) < 2 {
+		return nil, fmt.Errorf("numeral system needs at least 2 digits, not %q", digits)
+	}
+	for i, r := range s.digits {
+		if _, ok := s.values[r]; ok {
+			return nil, fmt.Errorf("digit %q appears twice in %q", r, digits)
+		}
+		s.values[r] = lowest + i
+	}
+	highest := lowest + len(s.digits) - 1
+	if !(lowest == 0 || lowest < 0 && highest > 0) {
+		return nil, fmt.Errorf("digits from %d to %d are neither standard nor balanced", lowest, highest)
+	}
+	if s.IsStandard() {
+		if _, ok := s.values['-']; ok {
+			return nil, errors.New("standard numeral system cannot use '-' as a digit as it is the sign")
+		}
+	}
+	return s, nil
+}
+
+// MustNew is like New but panics if the digits do not make a numeral system, for systems known when the program is
+// written
+func MustNew(digits string, lowest int) *System {
+	s, err := New(digits, lowest)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Base returns the number of digits in the system
+func (s *System) Base() int {
+	return len(s.digits)
+}
+
+// IsStandard returns whether the system has no negative digits and so writes negative numbers with a sign
+func (s *System) IsStandard() bool {
+	return s.lowest == 0
+}
+
+// SyntaxError is returned when a numeral cannot be read, giving the offset in runes of the problem
+type SyntaxError struct {
+	Numeral string
+	Offset  int
+	Msg     string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("numeral %q at offset %d: %s", e.Numeral, e.Offset, e.Msg)
+}
+
+// columns returns the values of the digits of a numeral, least significant first, negated for a negative standard
+// numeral
+func (s *System) columns(numeral string) ([]int, error) {
+	runes := []rune(numeral)
+	sign, start := 1, 0
+	if s.IsStandard() && len(runes) > 0 && runes[0] == '-' {
+		sign, start = -1, 1
+	}
+	if len(runes) == start {
+		return nil, &SyntaxError{Numeral: numeral, Offset: start, Msg: "no digits"}
+	}
+	columns := make([]int, len(runes)-start)
+	for i, r := range runes[start:] {
+		v, ok := s.values[r]
+		if !ok {
+			return nil, &SyntaxError{Numeral: numeral, Offset: start + i, Msg: fmt.Sprintf("%q is not a digit of %s", r, s)}
+		}
+		columns[len(columns)-1-i] = sign * v
+	}
+	return columns, nil
+}
+
+// Validate returns an error if the numeral is not written in the digits of the system
+func (s *System) Validate(numeral string) error {
+	_, err := s.columns(numeral)
+	return err
+}
+
+// Parse returns the value of a numeral
+func (s *System) Parse(numeral string) (*big.Int, error) {
+	columns, err := s.columns(numeral)
+	if err != nil {
+		return nil, err
+	}
+	n := new(big.Int)
+	base := big.NewInt(int64(s.Base()))
+	for i := len(columns) - 1; i >= 0; i-- {
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(columns[i])))
+	}
+	return n, nil
+}
+
+// Format returns the numeral of a value
+func (s *System) Format(n *big.Int) string {
+	if n.Sign() == 0 {
+		return string(s.digits[-s.lowest])
+	}
+	v := new(big.Int).Set(n)
+	negative := s.IsStandard() && v.Sign() < 0
+	if negative {
+		v.Neg(v)
+	}
+	base := big.NewInt(int64(s.Base()))
+	lowest := big.NewInt(int64(s.lowest))
+	digit := new(big.Int)
+	var columns []int
+	for v.Sign() != 0 {
+		// Shift the digit into range before taking the remainder, so that it may be negative
+		digit.Sub(v, lowest)
+		digit.Mod(digit, base)
+		digit.Add(digit, lowest)
+		columns = append(columns, int(digit.Int64()))
+		v.Sub(v, digit)
+		v.Div(v, base)
+	}
+	return s.write(columns, negative)
+}
+
+// FormatInt returns the numeral of an integer
+func (s *System) FormatInt(n int64) string {
+	return s.Format(big.NewInt(n))
+}
+
+// write returns the numeral of digit values, least significant first, without leading zeros
+func (s *System) write(columns []int, negative bool) string {
+	for len(columns) > 1 && columns[len(columns)-1] == 0 {
+		columns = columns[:len(columns)-1]
+	}
+	var sb strings.Builder
+	if negative && !(len(columns) == 1 && columns[0] == 0) {
+		sb.WriteByte('-')
+	}
+	for i := len(columns) - 1; i >= 0; i-- {
+		sb.WriteRune(s.digits[columns[i]-s.lowest])
+	}
+	return sb.String()
+}
+
+// normalise carries the column values of a sum so that each is a digit of the system.
+// It returns false if a standard system would need a negative number, which is left for the caller to negate.
+func (s *System) normalise(columns []int) ([]int, bool) {
+	base := s.Base()
+	digits := make([]int, 0, len(columns)+1)
+	carry := 0
+	for i := 0; i < len(columns) || carry != 0; i++ {
+		v := carry
+		if i < len(columns) {
+			v += columns[i]
+		} else if s.IsStandard() && carry < 0 {
+			return nil, false
+		}
+		d := ((v-s.lowest)%base+base)%base + s.lowest
+		digits = append(digits, d)
+		carry = (v - d) / base
+	}
+	return digits, true
+}
+
+// Add returns the sum of two numerals, added digit by digit without converting them to integers
+func (s *System) Add(a, b string) (string, error) {
+	return s.Sum(a, b)
+}
+
+// Sum returns the sum of any number of numerals, added digit by digit without converting them to integers
+func (s *System) Sum(numerals ...string) (string, error) {
+	var sum []int
+	for _, numeral := range numerals {
+		columns, err := s.columns(numeral)
+		if err != nil {
+			return "", err
+		}
+		for len(sum) < len(columns) {
+			sum = append(sum, 0)
+		}
+		for i, v := range columns {
+			sum[i] += v
+		}
+		// Carry after each numeral so that the columns stay small however many are added
+		digits, ok := s.normalise(sum)
+		if !ok {
+			// A negative standard sum is kept as the negated digits of its magnitude
+			digits, _ = s.normalise(negate(sum))
+			digits = negate(digits)
+		}
+		sum = digits
+	}
+	if len(sum) == 0 {
+		return string(s.digits[-s.lowest]), nil
+	}
+	if s.IsStandard() && isNegative(sum) {
+		return s.write(negate(sum), true), nil
+	}
+	return s.write(sum, false), nil
+}
+
+// negate returns the columns with every value negated
+func negate(columns []int) []int {
+	negated := make([]int, len(columns))
+	for i, v := range columns {
+		negated[i] = -v
+	}
+	return negated
+}
+
+// isNegative returns whether a sum in a standard system is held as negated digits
+func isNegative(columns []int) bool {
+	for _, v := range columns {
+		if v < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the digits of the system from lowest to highest value
+func (s *System) String() string {
+	return string(s.digits)
+}
@@ -0,0 +1,158 @@
+package numeral
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSnafu(t *testing.T) {
+	tests := map[int64]string{
+		1:         "1",
+		2:         "2",
+		3:         "1=",
+		4:         "1-",
+		5:         "10",
+		8:         "2=",
+		9:         "2-",
+		10:        "20",
+		15:        "1=0",
+		20:        "1-0",
+		2022:      "1=11-2",
+		12345:     "1-0---0",
+		314159265: "1121-1110-1=0",
+		-3:        "-2",
+	}
+	for n, want := range tests {
+		if got := Snafu.FormatInt(n); got != want {
+			t.Errorf("FormatInt(%d) = %s, want %s", n, got, want)
+		}
+		got, err := Snafu.Parse(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Int64() != n {
+			t.Errorf("Parse(%s) = %d, want %d", want, got, n)
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		system *System
+		input  []string
+		want   string
+	}{
+		{
+			system: Snafu,
+			input:  []string{"1=-0-2", "12111", "2=0=", "21", "2=01", "111", "20012", "112", "1=-1=", "1-12", "12", "1=", "122"},
+			want:   "2=-1=0",
+		},
+		{system: Decimal, input: []string{"999", "1"}, want: "1000"},
+		{system: Decimal, input: []string{"12", "-20"}, want: "-8"},
+		{system: Decimal, input: []string{"-5", "-7", "12"}, want: "0"},
+		{system: Decimal, input: []string{"-5", "007"}, want: "2"},
+		{system: Binary, input: []string{"1011", "1"}, want: "1100"},
+		{system: BalancedTernary, input: []string{"+-", "+-"}, want: "++"},
+		{system: Snafu, input: nil, want: "0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			got, err := tc.system.Sum(tc.input...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Sum(%q) = %s, want %s", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBig(t *testing.T) {
+	// Far beyond the range of an int
+	want, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	s := Snafu.Format(want)
+	got, err := Snafu.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(want) != 0 {
+		t.Errorf("Parse(Format(%s)) = %s", want, got)
+	}
+	sum, err := Snafu.Add(s, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Snafu.Parse(sum); got.Cmp(new(big.Int).Add(want, want)) != 0 {
+		t.Errorf("Add(%s, %s) = %s which is %s", s, s, sum, got)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		system  *System
+		numeral string
+		offset  int
+	}{
+		{system: Snafu, numeral: "", offset: 0},
+		{system: Snafu, numeral: "1=3", offset: 2},
+		{system: Snafu, numeral: "-=x", offset: 2},
+		{system: Decimal, numeral: "-", offset: 1},
+		{system: Decimal, numeral: "1-2", offset: 1},
+		{system: BalancedTernary, numeral: "+0+é", offset: 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.numeral, func(t *testing.T) {
+			err := tc.system.Validate(tc.numeral)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Validate(%q) error = %v, want a SyntaxError", tc.numeral, err)
+			}
+			if syntaxErr.Offset != tc.offset {
+				t.Errorf("Validate(%q) error at offset %d, want %d: %v", tc.numeral, syntaxErr.Offset, tc.offset, err)
+			}
+		})
+	}
+}
+
+func TestNewError(t *testing.T) {
+	tests := []struct {
+		digits string
+		lowest int
+	}{
+		{digits: "0", lowest: 0},
+		{digits: "0120", lowest: 0},
+		{digits: "123", lowest: 1},
+		{digits: "=-0", lowest: -2},
+		{digits: "0-", lowest: 0},
+	}
+	for _, tc := range tests {
+		if _, err := New(tc.digits, tc.lowest); err == nil {
+			t.Errorf("New(%q, %d) returned no error", tc.digits, tc.lowest)
+		}
+	}
+}
+
+// FuzzSum checks that adding numerals digit by digit matches adding their values
+func FuzzSum(f *testing.F) {
+	f.Add(int64(2022), int64(-12345), uint8(0))
+	f.Add(int64(-1), int64(1), uint8(1))
+	f.Add(int64(314159265), int64(4), uint8(3))
+	systems := []*System{Decimal, Binary, BalancedTernary, Snafu, MustNew("ab0cd", -3)}
+	f.Fuzz(func(t *testing.T, a, b int64, choice uint8) {
+		s := systems[int(choice)%len(systems)]
+		x, y := s.FormatInt(a), s.FormatInt(b)
+		if got, err := s.Parse(x); err != nil || got.Int64() != a {
+			t.Fatalf("Parse(FormatInt(%d)) = %v, %v", a, got, err)
+		}
+		sum, err := s.Add(x, y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+		if got := s.Format(want); got != sum {
+			t.Errorf("Add(%s, %s) = %s, want %s in %s", x, y, sum, got, s)
+		}
+	})
+}
@@ -0,0 +1,193 @@
+// Block letter recognition
+package ocr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+// ErrUnknownGlyph is returned when a letter does not match any known glyph
+var ErrUnknownGlyph = errors.New("unknown glyph")
+
+// smallGlyphs are the letters drawn six cells high, usually four cells wide
+var smallGlyphs = map[rune][]string{
+	'A': {".##.", "#..#", "#..#", "####", "#..#", "#..#"},
+	'B': {"###.", "#..#", "###.", "#..#", "#..#", "###."},
+	'C': {".##.", "#..#", "#...", "#...", "#..#", ".##."},
+	'E': {"####", "#...", "###.", "#...", "#...", "####"},
+	'F': {"####", "#...", "###.", "#...", "#...", "#..."},
+	'G': {".##.", "#..#", "#...", "#.##", "#..#", ".###"},
+	'H': {"#..#", "#..#", "####", "#..#", "#..#", "#..#"},
+	'I': {"###", ".#.", ".#.", ".#.", ".#.", "###"},
+	'J': {"..##", "...#", "...#", "...#", "#..#", ".##."},
+	'K': {"#..#", "#.#.", "##..", "#.#.", "#.#.", "#..#"},
+	'L': {"#...", "#...", "#...", "#...", "#...", "####"},
+	'O': {".##.", "#..#", "#..#", "#..#", "#..#", ".##."},
+	'P': {"###.", "#..#", "#..#", "###.", "#...", "#..."},
+	'R': {"###.", "#..#", "#..#", "###.", "#.#.", "#..#"},
+	'S': {".###", "#...", "#...", ".##.", "...#", "###."},
+	'U': {"#..#", "#..#", "#..#", "#..#", "#..#", ".##."},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#.."},
+	'Z': {"####", "...#", "..#.", ".#..", "#...", "####"},
+}
+
+// largeGlyphs are the letters drawn ten cells high and six cells wide
+var largeGlyphs = map[rune][]string{
+	'A': {"..##..", ".#..#.", "#....#", "#....#", "#....#", "######", "#....#", "#....#", "#....#", "#....#"},
+	'B': {"#####.", "#....#", "#....#", "#....#", "#####.", "#....#", "#....#", "#....#", "#....#", "#####."},
+	'C': {".####.", "#....#", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#....#", ".####."},
+	'E': {"######", "#.....", "#.....", "#.....", "#####.", "#.....", "#.....", "#.....", "#.....", "######"},
+	'F': {"######", "#.....", "#.....", "#.....", "#####.", "#.....", "#.....", "#.....", "#.....", "#....."},
+	'G': {".####.", "#....#", "#.....", "#.....", "#.....", "#..###", "#....#", "#....#", "#...##", ".###.#"},
+	'H': {"#....#", "#....#", "#....#", "#....#", "######", "#....#", "#....#", "#....#", "#....#", "#....#"},
+	'J': {"...###", "....#.", "....#.", "....#.", "....#.", "....#.", "#...#.", "#...#.", "#...#.", ".###.."},
+	'K': {"#....#", "#...#.", "#..#..", "#.#...", "##....", "##....", "#.#...", "#..#..", "#...#.", "#....#"},
+	'L': {"#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "######"},
+	'N': {"#....#", "##...#", "##...#", "#.#..#", "#.#..#", "#..#.#", "#..#.#", "#...##", "#...##", "#....#"},
+	'P': {"#####.", "#....#", "#....#", "#....#", "#####.", "#.....", "#.....", "#.....", "#.....", "#....."},
+	'R': {"#####.", "#....#", "#....#", "#....#", "#####.", "#..#..", "#...#.", "#...#.", "#....#", "#....#"},
+	'X': {"#....#", "#....#", ".#..#.", ".#..#.", "..##..", "..##..", ".#..#.", ".#..#.", "#....#", "#....#"},
+	'Z': {"######", ".....#", ".....#", "....#.", "...#..", "..#...", ".#....", "#.....", "#.....", "######"},
+}
+
+// fonts maps the height of the letters to their glyphs, keyed by the letters as drawn
+var fonts = map[int]map[string]rune{
+	6:  index(smallGlyphs),
+	10: index(largeGlyphs),
+}
+
+// index returns a lookup from each glyph drawn on a single line to its letter
+func index(glyphs map[rune][]string) map[string]rune {
+	lookup := map[string]rune{}
+	for r, rows := range glyphs {
+		lookup[strings.Join(rows, "\n")] = r
+	}
+	return lookup
+}
+
+// FromCoords returns a grid of the bounding box of a set of points, with the cells of the points lit
+func FromCoords[V any](points map[coordinates.Coord]V) [][]bool {
+	if len(points) == 0 {
+		return nil
+	}
+	coords := make([]coordinates.Coord, 0, len(points))
+	for c := range points {
+		coords = append(coords, c)
+	}
+	minCoord, maxCoord := coordinates.Range(coords)
+
+	grid := make([][]bool, maxCoord.Y-minCoord.Y+1)
+	for y := range grid {
+		grid[y] = make([]bool, maxCoord.X-minCoord.X+1)
+	}
+	for _, c := range coords {
+		grid[c.Y-minCoord.Y][c.X-minCoord.X] = true
+	}
+	return grid
+}
+
+// Render returns the grid drawn with # for lit and . for unlit cells, one line per row
+func Render(grid [][]bool) string {
+	var sb strings.Builder
+	for _, row := range grid {
+		for _, lit := range row {
+			if lit {
+				sb.WriteString("#")
+			} else {
+				sb.WriteString(".")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Answer returns the letters drawn in the grid, or if they cannot be recognised the grid rendered on the following
+// lines so it may be read by eye
+func Answer(grid [][]bool) string {
+	if text, err := Recognise(grid); err == nil {
+		return text
+	}
+	return "\n" + strings.TrimSuffix(Render(grid), "\n")
+}
+
+// trim returns the rows of the grid between the first and last rows with a lit cell, and the range of columns with a
+// lit cell
+func trim(grid [][]bool) ([][]bool, int, int) {
+	top, bottom := len(grid), -1
+	left, right := -1, -1
+	for y, row := range grid {
+		for x, lit := range row {
+			if !lit {
+				continue
+			}
+			top, bottom = min(top, y), max(bottom, y)
+			if left == -1 || x < left {
+				left = x
+			}
+			right = max(right, x)
+		}
+	}
+	if bottom == -1 {
+		return nil, 0, -1
+	}
+	return grid[top : bottom+1], left, right
+}
+
+// columnLit returns whether any cell in a column of the grid is lit
+func columnLit(grid [][]bool, x int) bool {
+	for _, row := range grid {
+		if x < len(row) && row[x] {
+			return true
+		}
+	}
+	return false
+}
+
+// Recognise returns the letters drawn in the grid.
+// Letters are separated by columns with no lit cells and may be six or ten cells high.
+func Recognise(grid [][]bool) (string, error) {
+	rows, left, right := trim(grid)
+	if rows == nil {
+		return "", fmt.Errorf("no letters drawn")
+	}
+	font, ok := fonts[len(rows)]
+	if !ok {
+		return "", fmt.Errorf("letters are %d cells high, only 6 or 10 are known", len(rows))
+	}
+
+	var sb strings.Builder
+	for x := left; x <= right; {
+		if !columnLit(rows, x) {
+			x++
+			continue
+		}
+		end := x
+		for end <= right && columnLit(rows, end) {
+			end++
+		}
+
+		lines := make([]string, len(rows))
+		for y, row := range rows {
+			var line strings.Builder
+			for i := x; i < end; i++ {
+				if i < len(row) && row[i] {
+					line.WriteString("#")
+				} else {
+					line.WriteString(".")
+				}
+			}
+			lines[y] = line.String()
+		}
+		r, ok := font[strings.Join(lines, "\n")]
+		if !ok {
+			return "", fmt.Errorf("letter %d at column %d: %w", sb.Len()+1, x, ErrUnknownGlyph)
+		}
+		sb.WriteRune(r)
+		x = end
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,123 @@
+package ocr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+// draw returns a grid of the letters drawn from a font with the given number of blank columns between them
+func draw(glyphs map[rune][]string, text string, gap int) [][]bool {
+	var grid [][]bool
+	for i, r := range text {
+		for y, line := range glyphs[r] {
+			if i == 0 {
+				grid = append(grid, nil)
+			} else {
+				grid[y] = append(grid[y], make([]bool, gap)...)
+			}
+			for _, c := range line {
+				grid[y] = append(grid[y], c == '#')
+			}
+		}
+	}
+	return grid
+}
+
+func TestGlyphs(t *testing.T) {
+	for _, glyphs := range []map[rune][]string{smallGlyphs, largeGlyphs} {
+		for r, lines := range glyphs {
+			grid := draw(glyphs, string(r), 0)
+			for x := range grid[0] {
+				if !columnLit(grid, x) {
+					t.Errorf("glyph %c has an empty column %d", r, x)
+				}
+			}
+			for y, line := range lines {
+				if len(line) != len(lines[0]) {
+					t.Errorf("glyph %c row %d has width %d, want %d", r, y, len(line), len(lines[0]))
+				}
+			}
+		}
+	}
+}
+
+func TestRecognise(t *testing.T) {
+	tests := []struct {
+		name   string
+		glyphs map[rune][]string
+		text   string
+		gap    int
+	}{
+		{name: "small", glyphs: smallGlyphs, text: "ABCEFGHIJKLOPRSUYZ", gap: 1},
+		{name: "large", glyphs: largeGlyphs, text: "ABCEFGHJKLNPRXZ", gap: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			grid := draw(tc.glyphs, tc.text, tc.gap)
+			// Pad with empty rows and columns, which are ignored
+			padded := [][]bool{make([]bool, len(grid[0])+2)}
+			for _, row := range grid {
+				padded = append(padded, append(append([]bool{false}, row...), false))
+			}
+			got, err := Recognise(padded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.text {
+				t.Errorf("Recognise() = %q, want %q", got, tc.text)
+			}
+		})
+	}
+}
+
+func TestFromCoords(t *testing.T) {
+	art := ".##..###.\n#..#.#..#\n#..#.###.\n####.#..#\n#..#.#..#\n#..#.###.\n"
+	points := map[coordinates.Coord]struct{}{}
+	for y, line := range strings.Split(strings.TrimSuffix(art, "\n"), "\n") {
+		for x, c := range line {
+			if c == '#' {
+				points[coordinates.Coord{X: x + 10, Y: y - 5}] = struct{}{}
+			}
+		}
+	}
+	grid := FromCoords(points)
+	if got := Render(grid); got != art {
+		t.Errorf("Render(FromCoords()) = %q, want %q", got, art)
+	}
+	if got, err := Recognise(grid); err != nil || got != "AB" {
+		t.Errorf("Recognise(FromCoords()) = %q, %v, want \"AB\"", got, err)
+	}
+}
+
+func TestRecogniseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		art  string
+	}{
+		{name: "empty", art: "....\n...."},
+		{name: "height", art: "#\n#\n#"},
+		{name: "unknown", art: "#..#\n#..#\n.##.\n.##.\n#..#\n#..#"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var grid [][]bool
+			for _, line := range strings.Split(tc.art, "\n") {
+				row := make([]bool, len(line))
+				for x, c := range line {
+					row[x] = c == '#'
+				}
+				grid = append(grid, row)
+			}
+			if _, err := Recognise(grid); err == nil {
+				t.Errorf("Recognise(%q) returned no error", tc.art)
+			}
+		})
+	}
+	grid := [][]bool{{true, false}, {true, false}, {true, false}, {true, false}, {true, false}, {true, true}}
+	if _, err := Recognise(grid); !errors.Is(err, ErrUnknownGlyph) {
+		t.Errorf("Recognise() = %v, want %v", err, ErrUnknownGlyph)
+	}
+}
@@ -0,0 +1,197 @@
+// Path overlay rendering
+package overlay
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maze-mapper/advent-of-code/animate"
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+// arrows maps a single step in each direction to the arrow drawn for it
+var arrows = map[coordinates.Coord]rune{
+	{X: 0, Y: -1}: '^',
+	{X: 1, Y: 0}:  '>',
+	{X: 0, Y: 1}:  'v',
+	{X: -1, Y: 0}: '<',
+}
+
+// Arrows returns marks for a path with each tile drawn as an arrow pointing to the next tile.
+// Steps which stay in place or move further than a neighbouring tile are not marked, nor is the last tile of the path.
+// Where the path crosses itself the later step is drawn.
+func Arrows(path []coordinates.Coord) map[coordinates.Coord]rune {
+	marks := map[coordinates.Coord]rune{}
+	for i := 0; i+1 < len(path); i++ {
+		step := coordinates.Coord{X: path[i+1].X - path[i].X, Y: path[i+1].Y - path[i].Y}
+		if r, ok := arrows[step]; ok {
+			marks[path[i]] = r
+		}
+	}
+	return marks
+}
+
+// Tiles returns marks drawing each of a set of tiles as the given rune
+func Tiles[V any](tiles map[coordinates.Coord]V, r rune) map[coordinates.Coord]rune {
+	marks := make(map[coordinates.Coord]rune, len(tiles))
+	for c := range tiles {
+		marks[c] = r
+	}
+	return marks
+}
+
+// Overlay returns a copy of the grid with the marks drawn over it.
+// Marks which fall outside the grid are ignored.
+func Overlay(grid [][]rune, marks map[coordinates.Coord]rune) [][]rune {
+	out := make([][]rune, len(grid))
+	for y, row := range grid {
+		out[y] = make([]rune, len(row))
+		copy(out[y], row)
+	}
+	for c, r := range marks {
+		if c.Y >= 0 && c.Y < len(out) && c.X >= 0 && c.X < len(out[c.Y]) {
+			out[c.Y][c.X] = r
+		}
+	}
+	return out
+}
+
+// Text returns the grid with the marks drawn over it, one line per row
+func Text(grid [][]rune, marks map[coordinates.Coord]rune) string {
+	var sb strings.Builder
+	for _, row := range Overlay(grid, marks) {
+		sb.WriteString(string(row))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// scale returns the size in pixels of each cell so that the whole grid fits in the largest image size
+func scale(grid [][]rune) (int, int, int) {
+	width := 1
+	for _, row := range grid {
+		width = max(width, len(row))
+	}
+	height := max(1, len(grid))
+	return width, height, animate.CellSize(width, height)
+}
+
+// WritePNG writes the grid with the marks drawn over it as a PNG image, with each cell a square of pixels coloured
+// from the palette
+func WritePNG(w io.Writer, grid [][]rune, marks map[coordinates.Coord]rune, palette animate.Palette) error {
+	width, height, size := scale(grid)
+	img := image.NewRGBA(image.Rect(0, 0, width*size, height*size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	for y, row := range Overlay(grid, marks) {
+		for x, r := range row {
+			cell := image.Rect(x*size, y*size, (x+1)*size, (y+1)*size)
+			draw.Draw(img, cell, image.NewUniform(palette.Colour(r)), image.Point{}, draw.Src)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// hex returns a colour written as #rrggbb
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// WriteSVG writes the grid as an SVG image of cells coloured from the palette, with the marks drawn over it as text
+func WriteSVG(w io.Writer, grid [][]rune, marks map[coordinates.Coord]rune, palette animate.Palette) error {
+	width, height, size := scale(grid)
+	size = max(size, animate.MaxCellSize)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"monospace\" font-size=\"%d\" text-anchor=\"middle\" dominant-baseline=\"central\">\n",
+		width*size, height*size, size,
+	)
+	fmt.Fprintf(&sb, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", hex(color.Black))
+	for y, row := range grid {
+		for x, r := range row {
+			fmt.Fprintf(&sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+				x*size, y*size, size, size, hex(palette.Colour(r)),
+			)
+		}
+	}
+	// Marks are written in row order so the output does not depend on the order of the map
+	for y, row := range grid {
+		for x := range row {
+			r, ok := marks[coordinates.Coord{X: x, Y: y}]
+			if !ok {
+				continue
+			}
+			var text strings.Builder
+			if err := xml.EscapeText(&text, []byte(string(r))); err != nil {
+				return err
+			}
+			fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" fill=\"%s\">%s</text>\n",
+				x*size+size/2, y*size+size/2, hex(palette.Colour(r)), text.String(),
+			)
+		}
+	}
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Save writes the grid with the marks drawn over it to a file, as a PNG or SVG image if the path ends in .png or .svg
+// and as text otherwise
+func Save(path string, grid [][]rune, marks map[coordinates.Coord]rune, palette animate.Palette) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		err = WritePNG(f, grid, marks, palette)
+	case ".svg":
+		err = WriteSVG(f, grid, marks, palette)
+	default:
+		_, err = io.WriteString(f, Text(grid, marks))
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// destination is where the package level Draw writes, empty unless drawing has been started
+var destination string
+
+// Start begins drawing the paths passed to Draw to the destination.
+// A destination of "-" prints the paths as text, otherwise each path is saved to a file named from the destination
+// and the label given to Draw.
+func Start(dest string) {
+	destination = dest
+}
+
+// Enabled returns whether paths are being drawn, so solvers can avoid building grids which are not needed
+func Enabled() bool {
+	return destination != ""
+}
+
+// Draw draws the marks over the grid if drawing has been started.
+// The label distinguishes the paths drawn by a single puzzle, such as "part1" and "part2".
+func Draw(label string, grid [][]rune, marks map[coordinates.Coord]rune) error {
+	switch destination {
+	case "":
+		return nil
+	case "-":
+		fmt.Printf("%s:\n%s", label, Text(grid, marks))
+		return nil
+	}
+	ext := filepath.Ext(destination)
+	path := strings.TrimSuffix(destination, ext) + "-" + label + ext
+	return Save(path, grid, marks, animate.DefaultPalette)
+}
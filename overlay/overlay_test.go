@@ -0,0 +1,99 @@
+package overlay
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maze-mapper/advent-of-code/animate"
+	"github.com/maze-mapper/advent-of-code/coordinates"
+)
+
+var grid = [][]rune{
+	[]rune("S.."),
+	[]rune("##."),
+	[]rune("E.."),
+}
+
+var path = []coordinates.Coord{
+	{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}, {X: 0, Y: 2},
+}
+
+func TestText(t *testing.T) {
+	want := ">>v\n##v\nE<<\n"
+	if got := Text(grid, Arrows(path)); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	// The grid is left unchanged
+	if string(grid[0]) != "S.." {
+		t.Errorf("grid row changed to %q", string(grid[0]))
+	}
+}
+
+func TestTiles(t *testing.T) {
+	tiles := map[coordinates.Coord]bool{{X: 1, Y: 0}: true, {X: 5, Y: 5}: true}
+	want := "SO.\n##.\nE..\n"
+	if got := Text(grid, Tiles(tiles, 'O')); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, grid, Arrows(path), animate.DefaultPalette); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Dx(); got != 3*animate.MaxCellSize {
+		t.Errorf("image is %d pixels wide, want %d", got, 3*animate.MaxCellSize)
+	}
+	r, g, b, _ := img.At(animate.MaxCellSize, animate.MaxCellSize).RGBA()
+	wr, wg, wb, _ := animate.DefaultPalette['#'].RGBA()
+	if r != wr || g != wg || b != wb {
+		t.Errorf("wall cell has colour %d %d %d, want %d %d %d", r, g, b, wr, wg, wb)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, grid, Arrows(path), animate.DefaultPalette); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("WriteSVG() wrote %q, want an svg element", svg)
+	}
+	if got := strings.Count(svg, "<text "); got != 6 {
+		t.Errorf("WriteSVG() drew %d marks, want 6", got)
+	}
+	// Arrows are escaped
+	if !strings.Contains(svg, ">&lt;</text>") || !strings.Contains(svg, ">&gt;</text>") {
+		t.Errorf("WriteSVG() did not escape the arrows: %q", svg)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	dir := t.TempDir()
+	Start(filepath.Join(dir, "route.txt"))
+	defer Start("")
+
+	if !Enabled() {
+		t.Fatal("Enabled() = false after Start")
+	}
+	if err := Draw("part1", grid, Arrows(path)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "route-part1.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ">>v\n##v\nE<<\n"; string(data) != want {
+		t.Errorf("Draw() wrote %q, want %q", data, want)
+	}
+}